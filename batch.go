@@ -0,0 +1,228 @@
+package pipl
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// DefaultBatchConcurrency is the number of workers used when BatchOptions.Concurrency is not set
+const DefaultBatchConcurrency = 4
+
+// BatchOptions are the settings for running a batch of searches
+//
+// DO NOT CHANGE ORDER - Optimized for memory (malign)
+type BatchOptions struct {
+	// OnProgress (optional) is called after every item completes, calls are never concurrent
+	OnProgress func(progress BatchProgress)
+
+	// Concurrency is the maximum number of searches in flight (default: DefaultBatchConcurrency)
+	Concurrency int
+
+	// ItemTimeout (optional) is the maximum duration for a single search
+	ItemTimeout time.Duration
+
+	// RequestsPerSecond (optional) limits how fast new searches are started (0 is unlimited)
+	RequestsPerSecond float64
+
+	// FailFast stops the batch on the first error, otherwise every item is attempted (best-effort)
+	FailFast bool
+}
+
+// BatchResult pairs a searched person (by index) with its response or error. With DecodeModeStrict,
+// a result can have both the Response and the *UnknownFieldsError (it counts as succeeded).
+//
+// DO NOT CHANGE ORDER - Optimized for memory (malign)
+type BatchResult struct {
+	Err      error     `json:"-"`
+	Person   *Person   `json:"person"`
+	Response *Response `json:"response,omitempty"`
+	Index    int       `json:"index"`
+}
+
+// BatchProgress is a snapshot of the batch, sent to the OnProgress callback
+type BatchProgress struct {
+	Completed int `json:"completed"`
+	Failed    int `json:"failed"`
+	Index     int `json:"index"`
+	Succeeded int `json:"succeeded"`
+	Total     int `json:"total"`
+}
+
+// BatchSearch runs Search() on the search service (client) for every person using a bounded pool of workers.
+// The results are in the same order as the people that were submitted, each
+// result contains either a Response or an error.
+//
+// In best-effort mode (default) the returned error is always nil (unless the context is canceled),
+// check each result for errors. In FailFast mode the first error stops the batch, remaining
+// items (and searches in flight) are marked with ErrBatchAborted and the first error is returned.
+func BatchSearch(ctx context.Context, service SearchService, people []*Person,
+	options BatchOptions,
+) ([]BatchResult, error) {
+	results := make([]BatchResult, len(people))
+	for index, person := range people {
+		results[index].Index = index
+		results[index].Person = person
+	}
+
+	// Nothing to search
+	if len(people) == 0 {
+		return results, nil
+	}
+
+	// Cancel any remaining work when the batch is done (or aborted)
+	batchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		firstErr error
+		mu       sync.Mutex
+		progress = BatchProgress{Total: len(people)}
+		wg       sync.WaitGroup
+	)
+
+	limiter := newRateLimiter(options.RequestsPerSecond)
+	jobs := make(chan int)
+
+	// Start the workers
+	for i := 0; i < batchConcurrency(options.Concurrency, len(people)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range jobs {
+				response, err := batchSearchItem(batchCtx, service, limiter, people[index], options.ItemTimeout)
+
+				mu.Lock()
+				failed := searchFailed(response, err)
+				if failed && firstErr != nil && ctx.Err() == nil && errors.Is(err, context.Canceled) {
+					err = ErrBatchAborted // Canceled by an earlier failure (not by the caller)
+				}
+				results[index].Response = response
+				results[index].Err = err
+				progress.Completed++
+				progress.Index = index
+				if failed {
+					progress.Failed++
+					if options.FailFast && firstErr == nil {
+						firstErr = err
+						cancel()
+					}
+				} else {
+					progress.Succeeded++
+				}
+				if options.OnProgress != nil {
+					options.OnProgress(progress)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	// Queue the jobs (stop queueing if the batch is aborted)
+	queued := 0
+queue:
+	for ; queued < len(people); queued++ {
+		select {
+		case jobs <- queued:
+		case <-batchCtx.Done():
+			break queue
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	// Mark all the items that never started
+	for index := queued; index < len(people); index++ {
+		if firstErr != nil {
+			results[index].Err = ErrBatchAborted
+		} else {
+			results[index].Err = ctx.Err()
+		}
+	}
+
+	if firstErr != nil {
+		return results, firstErr
+	}
+	return results, ctx.Err()
+}
+
+// batchSearchItem runs a single search, honoring the rate limit and item timeout
+func batchSearchItem(ctx context.Context, service SearchService, limiter *rateLimiter, person *Person,
+	timeout time.Duration,
+) (*Response, error) {
+	// Nothing to search
+	if person == nil {
+		return nil, ErrMissingPerson
+	}
+
+	// Wait for our turn
+	if err := limiter.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	// Apply the per-item timeout
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	return service.Search(ctx, person)
+}
+
+// batchConcurrency returns the number of workers to use for the given number of items
+func batchConcurrency(concurrency, items int) int {
+	if concurrency <= 0 {
+		concurrency = DefaultBatchConcurrency
+	}
+	if concurrency > items {
+		concurrency = items
+	}
+	return concurrency
+}
+
+// rateLimiter spaces out requests so that no more than the given number start per second
+type rateLimiter struct {
+	interval time.Duration
+	mu       sync.Mutex
+	next     time.Time
+}
+
+// newRateLimiter will create a new limiter (nil means unlimited)
+func newRateLimiter(requestsPerSecond float64) *rateLimiter {
+	if requestsPerSecond <= 0 {
+		return nil
+	}
+	return &rateLimiter{interval: time.Duration(float64(time.Second) / requestsPerSecond)}
+}
+
+// wait blocks until the next request is allowed to start, or the context is done
+func (r *rateLimiter) wait(ctx context.Context) error {
+	if r == nil {
+		return ctx.Err()
+	}
+
+	// Reserve the next slot
+	r.mu.Lock()
+	now := time.Now()
+	if r.next.Before(now) {
+		r.next = now
+	}
+	delay := r.next.Sub(now)
+	r.next = r.next.Add(r.interval)
+	r.mu.Unlock()
+
+	if delay <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,205 @@
+package pipl
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestPeople will create search people from a list of emails
+func newTestPeople(t testing.TB, emails ...string) []*Person {
+	people := make([]*Person, 0, len(emails))
+	for _, email := range emails {
+		person := NewPerson()
+		require.NoError(t, person.AddEmail(email))
+		people = append(people, person)
+	}
+	return people
+}
+
+// testSearchService is a search service that runs the search function (for any search)
+type testSearchService struct {
+	search func(ctx context.Context, searchPerson *Person) (*Response, error)
+}
+
+// Search will run the search function
+func (s *testSearchService) Search(ctx context.Context, searchPerson *Person) (*Response, error) {
+	return s.search(ctx, searchPerson)
+}
+
+// SearchAllPossiblePeople will run the search function
+func (s *testSearchService) SearchAllPossiblePeople(ctx context.Context, searchPerson *Person) (*Response, error) {
+	return s.search(ctx, searchPerson)
+}
+
+// SearchByPointer is not supported
+func (s *testSearchService) SearchByPointer(_ context.Context, _ string) (*Response, error) {
+	return nil, ErrInvalidSearchPointer
+}
+
+// TestBatchSearch will test the method BatchSearch()
+func TestBatchSearch(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no people", func(t *testing.T) {
+		mock := &searchResponse{}
+		c := NewClient(WithAPIKey(testKey), WithHTTPClient(mock))
+
+		results, err := BatchSearch(context.Background(), c, nil, BatchOptions{})
+		require.NoError(t, err)
+		assert.Empty(t, results)
+		assert.Equal(t, int32(0), mock.calls.Load())
+	})
+
+	t.Run("best-effort, ordered results", func(t *testing.T) {
+		mock := &searchResponse{}
+		c := NewClient(WithAPIKey(testKey), WithHTTPClient(mock))
+
+		people := newTestPeople(t, "one@example.com", "two@"+testFailDomain, "three@example.com")
+		people = append(people, nil)
+
+		var calls []BatchProgress
+		results, err := BatchSearch(context.Background(), c, people, BatchOptions{
+			Concurrency: 2,
+			OnProgress: func(progress BatchProgress) {
+				calls = append(calls, progress)
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, results, 4)
+
+		for index, result := range results {
+			assert.Equal(t, index, result.Index)
+			assert.Same(t, people[index], result.Person)
+		}
+
+		require.NoError(t, results[0].Err)
+		require.NotNil(t, results[0].Response)
+		require.ErrorIs(t, results[1].Err, ErrAPIResponse)
+		require.Nil(t, results[1].Response)
+		require.NoError(t, results[2].Err)
+		require.ErrorIs(t, results[3].Err, ErrMissingPerson)
+
+		require.Len(t, calls, 4)
+		last := calls[len(calls)-1]
+		assert.Equal(t, BatchProgress{Completed: 4, Failed: 2, Index: last.Index, Succeeded: 2, Total: 4}, last)
+		assert.Equal(t, int32(3), mock.calls.Load())
+	})
+
+	t.Run("strict mode keeps the response with the unknown fields", func(t *testing.T) {
+		c := NewClient(WithAPIKey(testKey), WithHTTPClient(&rawResponse{body: testUnknownFieldsJSON}),
+			WithDecodeMode(DecodeModeStrict))
+
+		var last BatchProgress
+		results, err := BatchSearch(context.Background(), c, newTestPeople(t, "one@example.com", "two@example.com"),
+			BatchOptions{FailFast: true, OnProgress: func(progress BatchProgress) { last = progress }})
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		for _, result := range results {
+			require.ErrorIs(t, result.Err, ErrUnknownFields)
+			require.NotNil(t, result.Response)
+			assert.Equal(t, "Clark Kent", result.Response.Person.Names[0].Display)
+		}
+		assert.Equal(t, 2, last.Succeeded)
+		assert.Equal(t, 0, last.Failed)
+	})
+
+	t.Run("fail-fast", func(t *testing.T) {
+		mock := &searchResponse{}
+		c := NewClient(WithAPIKey(testKey), WithHTTPClient(mock))
+
+		emails := []string{"first@" + testFailDomain}
+		for i := 0; i < 20; i++ {
+			emails = append(emails, fmt.Sprintf("person%d@example.com", i))
+		}
+
+		results, err := BatchSearch(context.Background(), c, newTestPeople(t, emails...), BatchOptions{
+			Concurrency: 1,
+			FailFast:    true,
+		})
+		require.ErrorIs(t, err, ErrAPIResponse)
+		require.Len(t, results, len(emails))
+		require.ErrorIs(t, results[0].Err, ErrAPIResponse)
+		require.ErrorIs(t, results[len(results)-1].Err, ErrBatchAborted)
+		assert.Less(t, mock.calls.Load(), int32(len(emails)))
+	})
+
+	t.Run("fail-fast, searches in flight are aborted", func(t *testing.T) {
+		var started sync.WaitGroup
+		started.Add(2)
+		service := &testSearchService{search: func(ctx context.Context, searchPerson *Person) (*Response, error) {
+			if strings.HasSuffix(searchPerson.Emails[0].Address, testFailDomain) {
+				started.Wait() // The other searches are in flight
+				return nil, ErrAPIResponse
+			}
+			started.Done()
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}}
+
+		results, err := BatchSearch(context.Background(), service,
+			newTestPeople(t, "one@example.com", "two@"+testFailDomain, "three@example.com"),
+			BatchOptions{Concurrency: 3, FailFast: true},
+		)
+		require.ErrorIs(t, err, ErrAPIResponse)
+		require.Len(t, results, 3)
+		require.ErrorIs(t, results[0].Err, ErrBatchAborted)
+		require.ErrorIs(t, results[1].Err, ErrAPIResponse)
+		require.ErrorIs(t, results[2].Err, ErrBatchAborted)
+	})
+
+	t.Run("item timeout", func(t *testing.T) {
+		mock := &searchResponse{delay: 200 * time.Millisecond}
+		c := NewClient(WithAPIKey(testKey), WithHTTPClient(mock))
+
+		results, err := BatchSearch(context.Background(), c, newTestPeople(t, "one@example.com"), BatchOptions{
+			ItemTimeout: 5 * time.Millisecond,
+		})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.ErrorIs(t, results[0].Err, context.DeadlineExceeded)
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		mock := &searchResponse{}
+		c := NewClient(WithAPIKey(testKey), WithHTTPClient(mock))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		results, err := BatchSearch(ctx, c, newTestPeople(t, "one@example.com", "two@example.com"), BatchOptions{})
+		require.ErrorIs(t, err, context.Canceled)
+		for _, result := range results {
+			require.Error(t, result.Err)
+		}
+	})
+
+	t.Run("rate limited", func(t *testing.T) {
+		mock := &searchResponse{}
+		c := NewClient(WithAPIKey(testKey), WithHTTPClient(mock))
+
+		start := time.Now()
+		results, err := BatchSearch(context.Background(), c, newTestPeople(t, "one@example.com", "two@example.com", "three@example.com"), BatchOptions{
+			Concurrency:       3,
+			RequestsPerSecond: 50,
+		})
+		require.NoError(t, err)
+		require.Len(t, results, 3)
+		assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+	})
+}
+
+// BenchmarkBatchSearch benchmarks the BatchSearch method
+func BenchmarkBatchSearch(b *testing.B) {
+	c := NewClient(WithAPIKey(testKey), WithHTTPClient(&searchResponse{}))
+	people := newTestPeople(b, "one@example.com", "two@example.com", "three@example.com", "four@example.com")
+	for i := 0; i < b.N; i++ {
+		_, _ = BatchSearch(context.Background(), c, people, BatchOptions{})
+	}
+}
@@ -0,0 +1,179 @@
+package pipl
+
+import (
+	"slices"
+	"time"
+)
+
+// RankInfo is what a ranking policy knows about a value (see Rankable)
+//
+// DO NOT CHANGE ORDER - Optimized for memory (malign)
+type RankInfo struct {
+	// Seen is the most recent date the value was seen (@last_seen, or @valid_since, see Recency)
+	Seen time.Time
+
+	// TypeRank is the position of the @type in the preferred types of the field (0 is the most preferred)
+	TypeRank int
+
+	// Current is the @current flag
+	Current bool
+
+	// Inferred is the @inferred flag
+	Inferred bool
+}
+
+// Rankable is any field type that can be ranked (names, addresses, emails, phones and jobs)
+type Rankable interface {
+	RankInfo() RankInfo
+}
+
+// RankingPolicy compares two values, it returns a negative number if a is better than b,
+// a positive number if b is better than a, and zero if they are equal
+type RankingPolicy func(a, b RankInfo) int
+
+// DefaultRankingPolicy ranks the values by:
+//
+//  1. @current values first
+//  2. Non-inferred values first
+//  3. The preferred @type of the field (see the RankInfo methods of each field type)
+//  4. The most recently seen (@last_seen, or @valid_since) first, values without a date last
+func DefaultRankingPolicy(a, b RankInfo) int {
+	switch {
+	case a.Current != b.Current:
+		return rankFirst(a.Current)
+	case a.Inferred != b.Inferred:
+		return rankFirst(!a.Inferred)
+	case a.TypeRank != b.TypeRank:
+		return a.TypeRank - b.TypeRank
+	}
+	return b.Seen.Compare(a.Seen)
+}
+
+// Rank returns a copy of the items sorted from the best to the worst (by the policy).
+// Equal items keep their original order.
+func Rank[T Rankable](items []T, policy RankingPolicy) []T {
+	ranked := slices.Clone(items)
+	slices.SortStableFunc(ranked, func(a, b T) int {
+		return policy(a.RankInfo(), b.RankInfo())
+	})
+	return ranked
+}
+
+// Best returns the best item (by the policy), or nil if there are no items.
+// The first of equal items wins.
+func Best[T Rankable](items []T, policy RankingPolicy) *T {
+	if len(items) == 0 {
+		return nil
+	}
+	best := 0
+	for index := 1; index < len(items); index++ {
+		if policy(items[index].RankInfo(), items[best].RankInfo()) < 0 {
+			best = index
+		}
+	}
+	return &items[best]
+}
+
+// PrimaryName returns the best name of the person (see DefaultRankingPolicy), or nil
+func (p *Person) PrimaryName() *Name {
+	return Best(p.Names, DefaultRankingPolicy)
+}
+
+// CurrentAddress returns the best @current address of the person (see DefaultRankingPolicy),
+// or nil and false if no address is current (use Best() for the best address)
+func (p *Person) CurrentAddress() (*Address, bool) {
+	return bestCurrent(p.Addresses, DefaultRankingPolicy)
+}
+
+// BestEmail returns the best email of the person (see DefaultRankingPolicy), or nil
+func (p *Person) BestEmail() *Email {
+	return Best(p.Emails, DefaultRankingPolicy)
+}
+
+// BestPhone returns the best phone of the person (see DefaultRankingPolicy), or nil
+func (p *Person) BestPhone() *Phone {
+	return Best(p.Phones, DefaultRankingPolicy)
+}
+
+// MobilePhones returns the mobile phones of the person, ranked from the best (see DefaultRankingPolicy)
+func (p *Person) MobilePhones() []Phone {
+	var mobiles []Phone
+	for _, phone := range p.Phones {
+		if phone.Type == PhoneTypeMobile {
+			mobiles = append(mobiles, phone)
+		}
+	}
+	return Rank(mobiles, DefaultRankingPolicy)
+}
+
+// CurrentJob returns the best @current job of the person (see DefaultRankingPolicy),
+// or nil and false if no job is current (use Best() for the best job)
+func (p *Person) CurrentJob() (*Job, bool) {
+	return bestCurrent(p.Jobs, DefaultRankingPolicy)
+}
+
+// bestCurrent returns the best @current item (by the policy), or nil and false if no item is current
+func bestCurrent[T Rankable](items []T, policy RankingPolicy) (*T, bool) {
+	var best *T
+	for index := range items {
+		if !items[index].RankInfo().Current {
+			continue
+		}
+		if best == nil || policy(items[index].RankInfo(), (*best).RankInfo()) < 0 {
+			best = &items[index]
+		}
+	}
+	return best, best != nil
+}
+
+// RankInfo returns the ranking info of the name (preferred types: present, none, maiden, former, alias)
+func (v Name) RankInfo() RankInfo {
+	return newRankInfo(v, v.Current, v.Inferred, typeRank(v.Type, "present", "", "maiden", "former", "alias"))
+}
+
+// RankInfo returns the ranking info of the address (preferred types: home, work, none, old)
+func (v Address) RankInfo() RankInfo {
+	return newRankInfo(v, v.Current, v.Inferred, typeRank(v.Type,
+		AddressTypeHome, AddressTypeWork, "", AddressTypeOld))
+}
+
+// RankInfo returns the ranking info of the email (preferred types: personal, work, none, then disposable emails)
+func (v Email) RankInfo() RankInfo {
+	rank := typeRank(v.Type, EmailTypePersonal, EmailTypeWork, "")
+	if v.Disposable {
+		rank += 3
+	}
+	return newRankInfo(v, v.Current, v.Inferred, rank)
+}
+
+// RankInfo returns the ranking info of the phone (preferred types: mobile, home, work, none, faxes, pager)
+func (v Phone) RankInfo() RankInfo {
+	return newRankInfo(v, v.Current, v.Inferred, typeRank(v.Type,
+		PhoneTypeMobile, PhoneTypeHomePhone, PhoneTypeWorkPhone, "", PhoneTypeHomeFax, PhoneTypeWorkFax, PhoneTypePager))
+}
+
+// RankInfo returns the ranking info of the job (jobs have no type)
+func (v Job) RankInfo() RankInfo {
+	return newRankInfo(v, v.Current, v.Inferred, 0)
+}
+
+// newRankInfo will create the ranking info (malformed dates are treated as not seen)
+func newRankInfo(item Dated, current, inferred bool, rank int) RankInfo {
+	return RankInfo{Current: current, Inferred: inferred, Seen: recencyTime(item), TypeRank: rank}
+}
+
+// typeRank returns the position of the type in the preferred types (unknown types are last)
+func typeRank[T ~string](value T, preferred ...T) int {
+	if index := slices.Index(preferred, value); index >= 0 {
+		return index
+	}
+	return len(preferred)
+}
+
+// rankFirst returns -1 if a is ranked first (true), 1 if not
+func rankFirst(a bool) int {
+	if a {
+		return -1
+	}
+	return 1
+}
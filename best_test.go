@@ -0,0 +1,121 @@
+package pipl
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPerson_BestValues will test the best value accessors
+func TestPerson_BestValues(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty person", func(t *testing.T) {
+		person := NewPerson()
+		assert.Nil(t, person.PrimaryName())
+		address, ok := person.CurrentAddress()
+		assert.Nil(t, address)
+		assert.False(t, ok)
+		assert.Nil(t, person.BestEmail())
+		assert.Nil(t, person.BestPhone())
+		job, ok := person.CurrentJob()
+		assert.Nil(t, job)
+		assert.False(t, ok)
+		assert.Empty(t, person.MobilePhones())
+	})
+
+	t.Run("primary name", func(t *testing.T) {
+		person := &Person{Names: []Name{
+			{Display: "Kal El", Type: "alias"},
+			{Display: "Clark Kent", Type: "present"},
+			{Display: "C. Kent", Type: "present", Inferred: true},
+		}}
+		require.NotNil(t, person.PrimaryName())
+		assert.Equal(t, "Clark Kent", person.PrimaryName().Display)
+	})
+
+	t.Run("current address", func(t *testing.T) {
+		person := &Person{Addresses: []Address{
+			{Display: "Old", Type: AddressTypeOld, LastSeen: "2020-01-01"},
+			{Display: "Work", Type: AddressTypeWork, LastSeen: "2019-01-01"},
+			{Display: "Current", Type: AddressTypeWork, Current: true},
+		}}
+		address, ok := person.CurrentAddress()
+		require.True(t, ok)
+		assert.Equal(t, "Current", address.Display)
+		assert.Same(t, &person.Addresses[2], address)
+
+		// No current address (the best address is not current)
+		person.Addresses[2].Current = false
+		address, ok = person.CurrentAddress()
+		assert.Nil(t, address)
+		assert.False(t, ok)
+		assert.Equal(t, "Work", Best(person.Addresses, DefaultRankingPolicy).Display)
+	})
+
+	t.Run("best email", func(t *testing.T) {
+		person := &Person{Emails: []Email{
+			{Address: "throwaway@example.com", Type: EmailTypePersonal, Disposable: true},
+			{Address: "clark@dailyplanet.com", Type: EmailTypeWork},
+			{Address: "clark@example.com", Type: EmailTypePersonal},
+		}}
+		assert.Equal(t, "clark@example.com", person.BestEmail().Address)
+	})
+
+	t.Run("phones", func(t *testing.T) {
+		person := &Person{Phones: []Phone{
+			{Display: "fax", Type: PhoneTypeWorkFax, Current: true},
+			{Display: "old mobile", Type: PhoneTypeMobile, ValidSince: "2010"},
+			{Display: "home", Type: PhoneTypeHomePhone},
+			{Display: "new mobile", Type: PhoneTypeMobile, LastSeen: "2019-05"},
+		}}
+		assert.Equal(t, "fax", person.BestPhone().Display)
+
+		mobiles := person.MobilePhones()
+		require.Len(t, mobiles, 2)
+		assert.Equal(t, "new mobile", mobiles[0].Display)
+		assert.Equal(t, "old mobile", mobiles[1].Display)
+		assert.Equal(t, "old mobile", person.Phones[1].Display) // Not sorted in place
+	})
+
+	t.Run("current job", func(t *testing.T) {
+		person := &Person{Jobs: []Job{
+			{Title: "Intern", ValidSince: "2001"},
+			{Title: "Reporter", ValidSince: "2005", LastSeen: "bad date", Current: true},
+			{Title: "Editor", ValidSince: "2010", Current: true},
+		}}
+		job, ok := person.CurrentJob()
+		require.True(t, ok)
+		assert.Equal(t, "Editor", job.Title)
+
+		person.Jobs[1].Current, person.Jobs[2].Current = false, false
+		_, ok = person.CurrentJob()
+		assert.False(t, ok)
+	})
+
+	t.Run("custom policy", func(t *testing.T) {
+		person := &Person{Phones: []Phone{
+			{Display: "mobile", Type: PhoneTypeMobile, Current: true},
+			{Display: "home", Type: PhoneTypeHomePhone, LastSeen: "2019"},
+		}}
+
+		// Only the recency matters
+		byRecency := func(a, b RankInfo) int {
+			return b.Seen.Compare(a.Seen)
+		}
+		assert.Equal(t, "home", Best(person.Phones, byRecency).Display)
+		assert.Equal(t, "mobile", Rank(person.Phones, byRecency)[1].Display)
+	})
+}
+
+// ExamplePerson_BestEmail example using BestEmail()
+func ExamplePerson_BestEmail() {
+	person := &Person{Emails: []Email{
+		{Address: "clark@dailyplanet.com", Type: EmailTypeWork},
+		{Address: "clark@example.com", Type: EmailTypePersonal},
+	}}
+	fmt.Println(person.BestEmail().Address)
+	// Output:clark@example.com
+}
@@ -0,0 +1,142 @@
+package pipl
+
+import (
+	"errors"
+	"strconv"
+)
+
+// PersonBuilder will build a search person with chained methods, and collect every validation
+// error (instead of checking the error of each Add method)
+//
+//	person, err := pipl.NewPersonBuilder().
+//		Name("Clark", "", "Kent", "", "").
+//		Email("clark@example.com").
+//		Phone(9785550145, 1).
+//		Build()
+type PersonBuilder struct {
+	counts map[string]int // Number of values added, by field (for the error paths)
+	errs   []error
+	person *Person
+}
+
+// NewPersonBuilder will create a new (empty) person builder
+func NewPersonBuilder() *PersonBuilder {
+	return &PersonBuilder{counts: make(map[string]int), person: NewPerson()}
+}
+
+// Name adds a name (see Person.AddName)
+func (b *PersonBuilder) Name(firstName, middleName, lastName, prefix, suffix string) *PersonBuilder {
+	return b.add("names", b.person.AddName(firstName, middleName, lastName, prefix, suffix))
+}
+
+// NameRaw adds a full name (see Person.AddNameRaw)
+func (b *PersonBuilder) NameRaw(fullName string) *PersonBuilder {
+	return b.add("names", b.person.AddNameRaw(fullName))
+}
+
+// Email adds an email address (see Person.AddEmail)
+func (b *PersonBuilder) Email(emailAddress string) *PersonBuilder {
+	return b.add("emails", b.person.AddEmail(emailAddress))
+}
+
+// Phone adds a phone number (see Person.AddPhone)
+func (b *PersonBuilder) Phone(phoneNumber int64, countryCode int) *PersonBuilder {
+	return b.add("phones", b.person.AddPhone(phoneNumber, countryCode))
+}
+
+// PhoneRaw adds a raw phone number (see Person.AddPhoneRaw)
+func (b *PersonBuilder) PhoneRaw(phoneNumber string) *PersonBuilder {
+	return b.add("phones", b.person.AddPhoneRaw(phoneNumber))
+}
+
+// Address adds an address (see Person.AddAddress)
+func (b *PersonBuilder) Address(house, street, apartment, city, state, country, poBox string) *PersonBuilder {
+	return b.add("addresses", b.person.AddAddress(house, street, apartment, city, state, country, poBox))
+}
+
+// AddressRaw adds a full address (see Person.AddAddressRaw)
+func (b *PersonBuilder) AddressRaw(fullAddress string) *PersonBuilder {
+	return b.add("addresses", b.person.AddAddressRaw(fullAddress))
+}
+
+// DOB sets the date of birth range, YYYY-MM-DD (see Person.SetDateOfBirth)
+func (b *PersonBuilder) DOB(startDate, endDate string) *PersonBuilder {
+	return b.set("dob", b.person.SetDateOfBirth(startDate, endDate))
+}
+
+// Gender sets the gender (see Person.SetGender)
+func (b *PersonBuilder) Gender(gender string) *PersonBuilder {
+	return b.set("gender", b.person.SetGender(gender))
+}
+
+// Username adds a username (see Person.AddUsername)
+func (b *PersonBuilder) Username(username, serviceProvider string) *PersonBuilder {
+	return b.add("usernames", b.person.AddUsername(username, serviceProvider))
+}
+
+// UserID adds a user id (see Person.AddUserID)
+func (b *PersonBuilder) UserID(userID, serviceProvider string) *PersonBuilder {
+	return b.add("user_ids", b.person.AddUserID(userID, serviceProvider))
+}
+
+// URL adds a url (see Person.AddURL)
+func (b *PersonBuilder) URL(url string) *PersonBuilder {
+	return b.add("urls", b.person.AddURL(url))
+}
+
+// Job adds a job (see Person.AddJob)
+func (b *PersonBuilder) Job(title, organization, industry, dateRangeStart, dateRangeEnd string) *PersonBuilder {
+	return b.add("jobs", b.person.AddJob(title, organization, industry, dateRangeStart, dateRangeEnd))
+}
+
+// Education adds an education (see Person.AddEducation)
+func (b *PersonBuilder) Education(degree, school, dateRangeStart, dateRangeEnd string) *PersonBuilder {
+	return b.add("educations", b.person.AddEducation(degree, school, dateRangeStart, dateRangeEnd))
+}
+
+// Language adds a language (see Person.AddLanguage)
+func (b *PersonBuilder) Language(languageCode, regionCode string) *PersonBuilder {
+	return b.add("languages", b.person.AddLanguage(languageCode, regionCode))
+}
+
+// Ethnicity adds an ethnicity (see Person.AddEthnicity)
+func (b *PersonBuilder) Ethnicity(ethnicity string) *PersonBuilder {
+	return b.add("ethnicities", b.person.AddEthnicity(ethnicity))
+}
+
+// OriginCountry adds an origin country (see Person.AddOriginCountry)
+func (b *PersonBuilder) OriginCountry(countryCode string) *PersonBuilder {
+	return b.add("origin_countries", b.person.AddOriginCountry(countryCode))
+}
+
+// Relationship adds a relationship (see Person.AddRelationship)
+func (b *PersonBuilder) Relationship(relationship Relationship) *PersonBuilder {
+	return b.add("relationships", b.person.AddRelationship(relationship))
+}
+
+// Build returns the search person, or all the validation errors (joined, each a *FieldError).
+// A person that does not meet the minimum search criteria returns ErrDoesNotMeetMinimumCriteria.
+func (b *PersonBuilder) Build() (*Person, error) {
+	if len(b.errs) > 0 {
+		return nil, errors.Join(b.errs...)
+	}
+	if !SearchMeetsMinimumCriteria(b.person) {
+		return nil, ErrDoesNotMeetMinimumCriteria
+	}
+	return b.person, nil
+}
+
+// add records the error of a list field value, with the index of the value in the path
+func (b *PersonBuilder) add(field string, err error) *PersonBuilder {
+	index := b.counts[field]
+	b.counts[field]++
+	return b.set(field+"["+strconv.Itoa(index)+"]", err)
+}
+
+// set records the error of a field
+func (b *PersonBuilder) set(path string, err error) *PersonBuilder {
+	if err != nil {
+		b.errs = append(b.errs, &FieldError{Err: err, Path: path})
+	}
+	return b
+}
@@ -0,0 +1,105 @@
+package pipl
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPersonBuilder will test building a search person
+func TestPersonBuilder(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid person", func(t *testing.T) {
+		person, err := NewPersonBuilder().
+			Name("Clark", "", "Kent", "", "").
+			Email(testEmail).
+			Phone(9785550145, 1).
+			Address("10", "Hickory Lane", "", "Smallville", "KS", "US", "").
+			DOB("1986-06-18", "1986-06-18").
+			Gender("male").
+			Job("Reporter", "Daily Planet", "", "", "").
+			Build()
+		require.NoError(t, err)
+		require.NotNil(t, person)
+		assert.Equal(t, "Clark", person.Names[0].First)
+		assert.Equal(t, testEmail, person.Emails[0].Address)
+		assert.Equal(t, int64(9785550145), person.Phones[0].Number)
+		assert.Equal(t, "Smallville", person.Addresses[0].City)
+		assert.Equal(t, "1986-06-18", person.DateOfBirth.DateRange.Start)
+		assert.Equal(t, "Daily Planet", person.Jobs[0].Organization)
+	})
+
+	t.Run("all errors are collected", func(t *testing.T) {
+		person, err := NewPersonBuilder().
+			Email(testEmail).
+			Email("not-an-email").
+			Phone(0, 1).
+			DOB("", "").
+			Build()
+		require.Error(t, err)
+		assert.Nil(t, person)
+		assert.ErrorIs(t, err, ErrInvalidEmailAddress)
+		assert.ErrorIs(t, err, ErrInvalidPhoneNumber)
+		assert.ErrorIs(t, err, ErrMissingBirthDate)
+		assert.Equal(t, "emails[1]: "+ErrInvalidEmailAddress.Error()+"\n"+
+			"phones[0]: "+ErrInvalidPhoneNumber.Error()+"\n"+
+			"dob: "+ErrMissingBirthDate.Error(), err.Error())
+
+		var fieldErr *FieldError
+		require.ErrorAs(t, err, &fieldErr)
+		assert.Equal(t, "emails[1]", fieldErr.Path)
+	})
+
+	t.Run("paths count per field", func(t *testing.T) {
+		_, err := NewPersonBuilder().
+			PhoneRaw("978-555-0145").
+			Phone(9785550145, 0).
+			Email(testEmail).
+			Build()
+		require.Error(t, err)
+		assert.Equal(t, "phones[1]: "+ErrMissingCountryCode.Error(), err.Error())
+	})
+
+	t.Run("minimum criteria", func(t *testing.T) {
+		person, err := NewPersonBuilder().Gender("male").Build()
+		require.ErrorIs(t, err, ErrDoesNotMeetMinimumCriteria)
+		assert.Nil(t, person)
+
+		person, err = NewPersonBuilder().Build()
+		require.ErrorIs(t, err, ErrDoesNotMeetMinimumCriteria)
+		assert.Nil(t, person)
+	})
+}
+
+// TestFieldError will test the field error
+func TestFieldError(t *testing.T) {
+	t.Parallel()
+
+	err := &FieldError{Err: ErrInvalidEmailAddress, Path: "emails[0]"}
+	assert.Equal(t, "emails[0]: "+ErrInvalidEmailAddress.Error(), err.Error())
+	assert.True(t, errors.Is(err, ErrInvalidEmailAddress))
+}
+
+// ExamplePersonBuilder example using NewPersonBuilder()
+func ExamplePersonBuilder() {
+	_, err := NewPersonBuilder().
+		Name("Clark", "", "Kent", "", "").
+		Email("clark").
+		Build()
+	var fieldErr *FieldError
+	if errors.As(err, &fieldErr) {
+		fmt.Println(fieldErr.Path)
+	}
+	// Output:emails[0]
+}
+
+// BenchmarkPersonBuilder benchmarks the method Build()
+func BenchmarkPersonBuilder(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, _ = NewPersonBuilder().Name("Clark", "", "Kent", "", "").Email(testEmail).Build()
+	}
+}
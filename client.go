@@ -28,6 +28,7 @@ type (
 	// ClientOptions holds all the configuration for client requests and default resources
 	ClientOptions struct {
 		apiKey        string         // The user's API key for NOWNode API
+		decodeMode    DecodeMode     // How unknown JSON fields in responses are handled
 		httpClient    HTTPInterface  // HTTP client interface
 		httpOptions   *HTTPOptions   // Options for the HTTP client
 		searchOptions *SearchOptions // contains search options
@@ -57,8 +58,9 @@ type (
 
 	// SearchOptions are custom search options for conducting searches
 	SearchOptions struct {
-		Search    *SearchParameters  // contains the search parameters that are submitted with your query, which may affect the data returned
-		Thumbnail *ThumbnailSettings // is for the thumbnail url settings
+		PossiblePersons *PossiblePersonsSettings // is for expanding possible persons in SearchAllPossiblePeople()
+		Search          *SearchParameters        // contains the search parameters that are submitted with your query, which may affect the data returned
+		Thumbnail       *ThumbnailSettings       // is for the thumbnail url settings
 	}
 )
 
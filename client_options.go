@@ -26,6 +26,10 @@ func DefaultHTTPOptions() (clientOptions *HTTPOptions) {
 // DefaultSearchOptions will return the default values for search options
 func DefaultSearchOptions() *SearchOptions {
 	return &SearchOptions{
+		PossiblePersons: &PossiblePersonsSettings{
+			Concurrency:   DefaultBatchConcurrency,
+			MaxExpansions: 0, // all possible persons
+		},
 		Search: &SearchParameters{
 			HideSponsored:              false,
 			InferPersons:               false,
@@ -93,3 +97,10 @@ func WithSearchOptions(options *SearchOptions) ClientOps {
 		}
 	}
 }
+
+// WithDecodeMode will set how unknown JSON fields in responses are handled (see DecodeMode)
+func WithDecodeMode(mode DecodeMode) ClientOps {
+	return func(c *ClientOptions) {
+		c.decodeMode = mode
+	}
+}
@@ -53,6 +53,10 @@ func TestDefaultSearchOptions(t *testing.T) {
 	assert.Equal(t, ThumbnailHeight, options.Thumbnail.Height)
 	assert.Equal(t, thumbnailEndpoint, options.Thumbnail.URL)
 	assert.Equal(t, ThumbnailWidth, options.Thumbnail.Width)
+
+	require.NotNil(t, options.PossiblePersons)
+	assert.Equal(t, DefaultBatchConcurrency, options.PossiblePersons.Concurrency)
+	assert.Equal(t, 0, options.PossiblePersons.MaxExpansions)
 }
 
 // TestWithAPIKey will test the method WithAPIKey()
@@ -181,3 +185,20 @@ func TestWithUserAgent(t *testing.T) {
 		assert.Equal(t, testUserAgent, options.userAgent)
 	})
 }
+
+// TestWithDecodeMode will test the method WithDecodeMode()
+func TestWithDecodeMode(t *testing.T) {
+	t.Parallel()
+
+	t.Run("check type", func(t *testing.T) {
+		opt := WithDecodeMode(DecodeModeDefault)
+		assert.IsType(t, *new(ClientOps), opt)
+	})
+
+	t.Run("test applying option", func(t *testing.T) {
+		options := &ClientOptions{}
+		opt := WithDecodeMode(DecodeModeStrict)
+		opt(options)
+		assert.Equal(t, DecodeModeStrict, options.decodeMode)
+	})
+}
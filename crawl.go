@@ -0,0 +1,221 @@
+package pipl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// DefaultCrawlDepth is the depth used when CrawlOptions.MaxDepth is not set
+const DefaultCrawlDepth = 1
+
+// CrawlOptions are the settings for crawling the relationship graph
+//
+// DO NOT CHANGE ORDER - Optimized for memory (malign)
+type CrawlOptions struct {
+	// MaxDepth is how many relationship hops are followed from the seed (default: DefaultCrawlDepth)
+	MaxDepth int
+
+	// MaxQueries is the budget of (billed) search pointer queries (0 is unlimited)
+	MaxQueries int
+}
+
+// CrawlNode is a person in the relationship graph. Resolved nodes have the full
+// person profile, unresolved nodes only have the relationship preview (no search
+// pointer, over the depth, or over the budget)
+//
+// DO NOT CHANGE ORDER - Optimized for memory (malign)
+type CrawlNode struct {
+	Person        *Person       `json:"person,omitempty"`
+	Preview       *Relationship `json:"preview,omitempty"`
+	ID            GUID          `json:"id,omitempty"`
+	Key           string        `json:"key"`
+	SearchPointer string        `json:"search_pointer,omitempty"`
+	Depth         int           `json:"depth"`
+}
+
+// CrawlEdge is a typed relationship between two nodes (by key)
+type CrawlEdge struct {
+	From    string           `json:"from"`
+	Subtype string           `json:"subtype,omitempty"`
+	To      string           `json:"to"`
+	Type    RelationshipType `json:"type,omitempty"`
+}
+
+// CrawlGraph is the de-duplicated graph of persons (nodes) and relationships (edges)
+//
+// DO NOT CHANGE ORDER - Optimized for memory (malign)
+type CrawlGraph struct {
+	Edges           []CrawlEdge `json:"edges"`
+	Nodes           []CrawlNode `json:"nodes"`
+	Queries         int         `json:"queries"`
+	BudgetExhausted bool        `json:"budget_exhausted"`
+}
+
+// crawler holds the state of a single crawl
+type crawler struct {
+	edges   map[CrawlEdge]bool
+	errs    []error
+	graph   *CrawlGraph
+	nodes   map[string]int // node key -> index in graph.Nodes
+	options CrawlOptions
+	service SearchService
+	visited map[string]string // search pointer -> node key (queried, or the pointer of a resolved person)
+}
+
+// Crawl follows the relationships of the seed person breadth-first, using each relationship's
+// search pointer to get the full profile of the related person (and then their relationships)
+// from the search service (client).
+//
+// The crawl stops at the depth limit or when the query budget is spent. Search pointers are
+// never queried twice (checked before each billed query) and persons are de-duplicated by
+// Person.ID (cycles are not followed twice). Previews are copies of the relationships. The
+// graph is always returned, failed search pointers are returned as a joined error.
+func Crawl(ctx context.Context, service SearchService, seed *Person, options CrawlOptions) (*CrawlGraph, error) {
+	// Nothing to crawl
+	if seed == nil {
+		return nil, ErrMissingPerson
+	}
+
+	// Set the defaults
+	if options.MaxDepth <= 0 {
+		options.MaxDepth = DefaultCrawlDepth
+	}
+
+	cr := &crawler{
+		edges:   make(map[CrawlEdge]bool),
+		graph:   &CrawlGraph{Edges: []CrawlEdge{}, Nodes: []CrawlNode{}},
+		nodes:   make(map[string]int),
+		options: options,
+		visited: make(map[string]string),
+		service: service,
+	}
+
+	// Start with the seed
+	queue := []string{cr.addPerson(seed, 0)}
+	for len(queue) > 0 {
+		if err := ctx.Err(); err != nil {
+			return cr.graph, err
+		}
+
+		key := queue[0]
+		queue = queue[1:]
+
+		// Follow the relationships (up to the depth limit)
+		node := cr.graph.Nodes[cr.nodes[key]]
+		if node.Person == nil || node.Depth >= options.MaxDepth {
+			continue
+		}
+		for index := range node.Person.Relationships {
+			relationship := &node.Person.Relationships[index]
+			toKey, fetched := cr.follow(ctx, relationship, node.Depth+1)
+			if len(toKey) == 0 {
+				continue
+			}
+			cr.addEdge(CrawlEdge{From: key, To: toKey, Type: relationship.Type, Subtype: relationship.Subtype})
+			if fetched {
+				queue = append(queue, toKey)
+			}
+		}
+	}
+
+	return cr.graph, errors.Join(cr.errs...)
+}
+
+// follow resolves the related person (if possible), returns the node key and
+// true if the node was newly fetched (and should be crawled)
+func (cr *crawler) follow(ctx context.Context, relationship *Relationship, depth int) (string, bool) {
+	pointer := relationship.SearchPointer
+
+	// Already visited this pointer (no billed query)
+	if key, ok := cr.visited[pointer]; ok {
+		return key, false
+	}
+
+	// Cannot (or should not) be resolved, keep the preview
+	if len(pointer) == 0 {
+		return cr.addPreview(relationship, depth), false
+	}
+	if cr.options.MaxQueries > 0 && cr.graph.Queries >= cr.options.MaxQueries {
+		cr.graph.BudgetExhausted = true
+		return cr.addPreview(relationship, depth), false
+	}
+
+	// Get the full person
+	cr.graph.Queries++
+	response, err := cr.service.SearchByPointer(ctx, pointer)
+	if err != nil {
+		cr.errs = append(cr.errs, fmt.Errorf("search pointer %s: %w", pointer, err))
+	}
+	if searchFailed(response, err) {
+		cr.visited[pointer] = cr.addPreview(relationship, depth)
+		return cr.visited[pointer], false
+	}
+	if len(response.Person.SearchPointer) == 0 {
+		response.Person.SearchPointer = pointer
+	}
+
+	// Cycle (or same person by another pointer)
+	key := crawlNodeKey(response.Person.ID, pointer)
+	if _, ok := cr.nodes[key]; ok {
+		cr.visited[pointer] = key
+		return key, false
+	}
+
+	cr.visited[pointer] = cr.addPerson(&response.Person, depth)
+	return cr.visited[pointer], true
+}
+
+// addPerson adds a resolved person to the graph
+func (cr *crawler) addPerson(person *Person, depth int) string {
+	key := crawlNodeKey(person.ID, person.SearchPointer)
+	if len(person.SearchPointer) > 0 {
+		cr.visited[person.SearchPointer] = key
+	}
+	if _, ok := cr.nodes[key]; !ok {
+		cr.nodes[key] = len(cr.graph.Nodes)
+		cr.graph.Nodes = append(cr.graph.Nodes, CrawlNode{
+			Depth:         depth,
+			ID:            person.ID,
+			Key:           key,
+			Person:        person,
+			SearchPointer: person.SearchPointer,
+		})
+	}
+	return key
+}
+
+// addPreview adds an unresolved person (a copy of the relationship preview) to the graph
+func (cr *crawler) addPreview(relationship *Relationship, depth int) string {
+	key := crawlNodeKey("", relationship.SearchPointer)
+	if len(relationship.SearchPointer) == 0 {
+		key = fmt.Sprintf("preview:%d", len(cr.graph.Nodes))
+	}
+	if _, ok := cr.nodes[key]; !ok {
+		cr.nodes[key] = len(cr.graph.Nodes)
+		cr.graph.Nodes = append(cr.graph.Nodes, CrawlNode{
+			Depth:         depth,
+			Key:           key,
+			Preview:       deepCopy(relationship),
+			SearchPointer: relationship.SearchPointer,
+		})
+	}
+	return key
+}
+
+// addEdge adds the edge (if not already added)
+func (cr *crawler) addEdge(edge CrawlEdge) {
+	if edge.From == edge.To || cr.edges[edge] {
+		return
+	}
+	cr.edges[edge] = true
+	cr.graph.Edges = append(cr.graph.Edges, edge)
+}
+
+// crawlNodeKey returns the key for a node (person ID, or search pointer if there is no ID)
+func crawlNodeKey(id GUID, searchPointer string) string {
+	if len(id) > 0 {
+		return string(id)
+	}
+	return "pointer:" + searchPointer
+}
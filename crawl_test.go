@@ -0,0 +1,164 @@
+package pipl
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testPointerClark = "1000000000000000000000000000000000001"
+	testPointerLois  = "1000000000000000000000000000000000002"
+	testPointerPerry = "1000000000000000000000000000000000003"
+	testPointerJimmy = "1000000000000000000000000000000000004"
+	testPointerLex   = "1000000000000000000000000000000000005"
+)
+
+// newTestCrawlPersons returns a small relationship network (with a cycle) keyed by search pointer
+func newTestCrawlPersons() map[string]Person {
+	return map[string]Person{
+		testPointerClark: {
+			ID:            "clark",
+			SearchPointer: testPointerClark,
+			Names:         []Name{{First: "Clark", Last: "Kent"}},
+			Relationships: []Relationship{
+				{Type: "friend", Subtype: "Girlfriend", SearchPointer: testPointerLois, Names: []Name{{First: "Lois", Last: "Lane"}}},
+				{Type: "family", Subtype: "Father", Names: []Name{{First: "Jonathan", Last: "Kent"}}},
+				{Type: "other", Subtype: "Archenemy", SearchPointer: testPointerLex, Names: []Name{{First: "Lex", Last: "Luthor"}}},
+			},
+		},
+		testPointerLois: {
+			ID:            "lois",
+			SearchPointer: testPointerLois,
+			Names:         []Name{{First: "Lois", Last: "Lane"}},
+			Relationships: []Relationship{
+				{Type: "friend", Subtype: "Boyfriend", SearchPointer: testPointerClark},
+				{Type: "work", Subtype: "Boss", SearchPointer: testPointerPerry},
+			},
+		},
+		testPointerPerry: {
+			ID:            "perry",
+			SearchPointer: testPointerPerry,
+			Names:         []Name{{First: "Perry", Last: "White"}},
+			Relationships: []Relationship{
+				{Type: "work", Subtype: "Employee", SearchPointer: testPointerJimmy},
+			},
+		},
+		testPointerJimmy: {
+			ID:            "jimmy",
+			SearchPointer: testPointerJimmy,
+			Names:         []Name{{First: "Jimmy", Last: "Olsen"}},
+		},
+	}
+}
+
+// TestCrawl will test the method Crawl()
+func TestCrawl(t *testing.T) {
+	t.Parallel()
+
+	persons := newTestCrawlPersons()
+	seed := persons[testPointerClark]
+
+	t.Run("missing seed", func(t *testing.T) {
+		c := NewClient(WithHTTPClient(&personsResponse{persons: persons}))
+		graph, err := Crawl(context.Background(), c, nil, CrawlOptions{})
+		require.ErrorIs(t, err, ErrMissingPerson)
+		require.Nil(t, graph)
+	})
+
+	t.Run("default depth", func(t *testing.T) {
+		mock := &personsResponse{persons: persons}
+		c := NewClient(WithAPIKey(testKey), WithHTTPClient(mock))
+
+		graph, err := Crawl(context.Background(), c, &seed, CrawlOptions{})
+		require.ErrorIs(t, err, ErrAPIResponse) // Lex is unknown
+		require.NotNil(t, graph)
+
+		assert.Equal(t, 2, graph.Queries)
+		assert.Equal(t, int32(2), mock.calls.Load())
+		require.Len(t, graph.Nodes, 4)
+		assert.Equal(t, "clark", graph.Nodes[0].Key)
+		assert.Equal(t, "lois", graph.Nodes[1].Key)
+		require.NotNil(t, graph.Nodes[1].Person)
+		assert.Nil(t, graph.Nodes[2].Person)
+		assert.Equal(t, "Jonathan", graph.Nodes[2].Preview.Names[0].First)
+		assert.Equal(t, "pointer:"+testPointerLex, graph.Nodes[3].Key)
+		assert.Equal(t, []CrawlEdge{
+			{From: "clark", To: "lois", Type: "friend", Subtype: "Girlfriend"},
+			{From: "clark", To: graph.Nodes[2].Key, Type: "family", Subtype: "Father"},
+			{From: "clark", To: "pointer:" + testPointerLex, Type: "other", Subtype: "Archenemy"},
+		}, graph.Edges)
+	})
+
+	t.Run("deeper with cycle", func(t *testing.T) {
+		mock := &personsResponse{persons: persons}
+		c := NewClient(WithAPIKey(testKey), WithHTTPClient(mock))
+
+		graph, err := Crawl(context.Background(), c, &seed, CrawlOptions{MaxDepth: 3})
+		require.Error(t, err)
+
+		keys := make([]string, 0, len(graph.Nodes))
+		for _, node := range graph.Nodes {
+			keys = append(keys, node.Key)
+		}
+		assert.ElementsMatch(t, []string{"clark", "lois", graph.Nodes[2].Key, "pointer:" + testPointerLex, "perry", "jimmy"}, keys)
+		assert.Contains(t, graph.Edges, CrawlEdge{From: "lois", To: "clark", Type: "friend", Subtype: "Boyfriend"})
+		assert.Contains(t, graph.Edges, CrawlEdge{From: "perry", To: "jimmy", Type: "work", Subtype: "Employee"})
+
+		// Clark is never fetched (seed), every other pointer is fetched once
+		assert.Equal(t, 4, graph.Queries)
+		assert.Equal(t, int32(4), mock.calls.Load())
+		assert.False(t, graph.BudgetExhausted)
+	})
+
+	t.Run("budget", func(t *testing.T) {
+		mock := &personsResponse{persons: persons}
+		c := NewClient(WithAPIKey(testKey), WithHTTPClient(mock))
+
+		graph, err := Crawl(context.Background(), c, &seed, CrawlOptions{MaxDepth: 3, MaxQueries: 1})
+		require.NoError(t, err)
+		assert.True(t, graph.BudgetExhausted)
+		assert.Equal(t, 1, graph.Queries)
+		assert.Equal(t, int32(1), mock.calls.Load())
+	})
+
+	t.Run("search pointers are queried once", func(t *testing.T) {
+		mock := &personsResponse{persons: persons}
+		c := NewClient(WithAPIKey(testKey), WithHTTPClient(mock))
+
+		repeated := seed
+		repeated.Relationships = append(append([]Relationship{}, seed.Relationships...), seed.Relationships...)
+		graph, err := Crawl(context.Background(), c, &repeated, CrawlOptions{MaxDepth: 2})
+		require.ErrorIs(t, err, ErrAPIResponse) // Lex is unknown (queried once)
+
+		// Lois, Lex and Perry (Clark is the seed and Lois links back to Clark)
+		assert.Equal(t, 3, graph.Queries)
+		assert.Equal(t, int32(3), mock.calls.Load())
+	})
+
+	t.Run("previews are copies", func(t *testing.T) {
+		c := NewClient(WithAPIKey(testKey), WithHTTPClient(&personsResponse{persons: persons}))
+		original := newTestCrawlPersons()[testPointerClark]
+		graph, err := Crawl(context.Background(), c, &original, CrawlOptions{MaxQueries: 1})
+		require.NoError(t, err)
+
+		for _, node := range graph.Nodes {
+			if node.Preview != nil {
+				node.Preview.Names[0].First = "Changed"
+			}
+		}
+		assert.Equal(t, newTestCrawlPersons()[testPointerClark], original)
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		c := NewClient(WithAPIKey(testKey), WithHTTPClient(&personsResponse{persons: persons}))
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		graph, err := Crawl(ctx, c, &seed, CrawlOptions{})
+		require.ErrorIs(t, err, context.Canceled)
+		require.Len(t, graph.Nodes, 1)
+	})
+}
@@ -0,0 +1,399 @@
+package pipl
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DatePrecision is how precise a date from Pipl is (Pipl uses YYYY, YYYY-MM and YYYY-MM-DD)
+type DatePrecision int
+
+const (
+	// DatePrecisionNone is an empty (missing) date
+	DatePrecisionNone DatePrecision = iota
+
+	// DatePrecisionYear is a date with only the year (YYYY)
+	DatePrecisionYear
+
+	// DatePrecisionMonth is a date with the year and month (YYYY-MM)
+	DatePrecisionMonth
+
+	// DatePrecisionDay is a full date (YYYY-MM-DD)
+	DatePrecisionDay
+
+	// DatePrecisionSecond is a full timestamp (YYYY-MM-DD HH:MM:SS or RFC 3339)
+	DatePrecisionSecond
+)
+
+// dateLayouts are the accepted date formats, by precision
+var dateLayouts = []struct { //nolint:gochecknoglobals // Lookup table for date parsing
+	layout    string
+	precision DatePrecision
+}{
+	{"2006", DatePrecisionYear},
+	{"2006-01", DatePrecisionMonth},
+	{"2006-01-02", DatePrecisionDay},
+	{"2006-01-02 15:04:05", DatePrecisionSecond},
+	{"2006-01-02T15:04:05", DatePrecisionSecond},
+	{time.RFC3339, DatePrecisionSecond},
+}
+
+// Date is a parsed (possibly partial) date. Time is the start of the period,
+// for example "2010-05" is May 1st 2010 (UTC) with DatePrecisionMonth.
+type Date struct {
+	Time      time.Time
+	Precision DatePrecision
+}
+
+// Dated is any field type that has the @valid_since and @last_seen dates
+type Dated interface {
+	LastSeenDate() (Date, error)
+	ValidSinceDate() (Date, error)
+}
+
+// String returns the name of the precision
+func (p DatePrecision) String() string {
+	switch p {
+	case DatePrecisionNone:
+		return "none"
+	case DatePrecisionYear:
+		return "year"
+	case DatePrecisionMonth:
+		return "month"
+	case DatePrecisionDay:
+		return "day"
+	case DatePrecisionSecond:
+		return "second"
+	}
+	return fmt.Sprintf("DatePrecision(%d)", int(p))
+}
+
+// ParseDate parses a Pipl date (YYYY, YYYY-MM, YYYY-MM-DD or a full timestamp).
+// An empty value returns a zero Date (DatePrecisionNone) and no error,
+// a malformed value returns ErrInvalidDate.
+func ParseDate(value string) (Date, error) {
+	value = strings.TrimSpace(value)
+	if len(value) == 0 {
+		return Date{}, nil
+	}
+	for _, format := range dateLayouts {
+		if t, err := time.Parse(format.layout, value); err == nil {
+			return Date{Time: t.UTC(), Precision: format.precision}, nil
+		}
+	}
+	return Date{}, fmt.Errorf("%w: %q", ErrInvalidDate, value)
+}
+
+// IsZero returns true if the date is empty (missing)
+func (d Date) IsZero() bool {
+	return d.Precision == DatePrecisionNone
+}
+
+// End returns the last instant of the period covered by the date,
+// for example "2010" ends at 2010-12-31 23:59:59.999999999 (UTC)
+func (d Date) End() time.Time {
+	switch d.Precision {
+	case DatePrecisionYear:
+		return d.Time.AddDate(1, 0, 0).Add(-time.Nanosecond)
+	case DatePrecisionMonth:
+		return d.Time.AddDate(0, 1, 0).Add(-time.Nanosecond)
+	case DatePrecisionDay:
+		return d.Time.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	case DatePrecisionNone, DatePrecisionSecond:
+	}
+	return d.Time
+}
+
+// String returns the date in the same format (and precision) that Pipl uses
+func (d Date) String() string {
+	switch d.Precision {
+	case DatePrecisionNone:
+		return ""
+	case DatePrecisionYear:
+		return d.Time.Format("2006")
+	case DatePrecisionMonth:
+		return d.Time.Format("2006-01")
+	case DatePrecisionDay:
+		return d.Time.Format("2006-01-02")
+	case DatePrecisionSecond:
+	}
+	return d.Time.Format(time.RFC3339)
+}
+
+// Recency returns the most relevant date of the item: @last_seen, or @valid_since if never seen
+func Recency(item Dated) (Date, error) {
+	lastSeen, err := item.LastSeenDate()
+	if err != nil || !lastSeen.IsZero() {
+		return lastSeen, err
+	}
+	return item.ValidSinceDate()
+}
+
+// SortByRecency sorts the items (in place) from the most to the least recent (see Recency).
+// Items without a date, or with a malformed date, are sorted last (in their original order).
+func SortByRecency[T Dated](items []T) {
+	slices.SortStableFunc(items, func(a, b T) int {
+		return recencyTime(b).Compare(recencyTime(a))
+	})
+}
+
+// FilterSince returns the items that are at least as recent as the given time (see Recency).
+// Partial dates count as recent if any part of the period is on or after the given time.
+// Items without a date, or with a malformed date, are not returned.
+func FilterSince[T Dated](items []T, since time.Time) []T {
+	filtered := make([]T, 0, len(items))
+	for _, item := range items {
+		if date, err := Recency(item); err == nil && !date.IsZero() && !date.End().Before(since) {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered
+}
+
+// MostRecent returns the most recent item (see Recency), false if no item has a valid date
+func MostRecent[T Dated](items []T) (T, bool) {
+	var best T
+	var bestTime time.Time
+	found := false
+	for _, item := range items {
+		if t := recencyTime(item); !t.IsZero() && (!found || t.After(bestTime)) {
+			best, bestTime, found = item, t, true
+		}
+	}
+	return best, found
+}
+
+// recencyTime returns the recency of the item as a time (zero if missing or malformed)
+func recencyTime(item Dated) time.Time {
+	date, err := Recency(item)
+	if err != nil {
+		return time.Time{}
+	}
+	return date.Time
+}
+
+// StartDate returns the parsed start of the date range
+func (d DateRange) StartDate() (Date, error) {
+	return ParseDate(d.Start)
+}
+
+// EndDate returns the parsed end of the date range
+func (d DateRange) EndDate() (Date, error) {
+	return ParseDate(d.End)
+}
+
+// ValidSinceDate returns the parsed @valid_since date of a Name
+func (v Name) ValidSinceDate() (Date, error) {
+	return ParseDate(v.ValidSince)
+}
+
+// LastSeenDate returns the parsed @last_seen date of a Name
+func (v Name) LastSeenDate() (Date, error) {
+	return ParseDate(v.LastSeen)
+}
+
+// ValidSinceDate returns the parsed @valid_since date of an Address
+func (v Address) ValidSinceDate() (Date, error) {
+	return ParseDate(v.ValidSince)
+}
+
+// LastSeenDate returns the parsed @last_seen date of an Address
+func (v Address) LastSeenDate() (Date, error) {
+	return ParseDate(v.LastSeen)
+}
+
+// ValidSinceDate returns the parsed @valid_since date of a Phone
+func (v Phone) ValidSinceDate() (Date, error) {
+	return ParseDate(v.ValidSince)
+}
+
+// LastSeenDate returns the parsed @last_seen date of a Phone
+func (v Phone) LastSeenDate() (Date, error) {
+	return ParseDate(v.LastSeen)
+}
+
+// ValidSinceDate returns the parsed @valid_since date of an Email
+func (v Email) ValidSinceDate() (Date, error) {
+	return ParseDate(v.ValidSince)
+}
+
+// LastSeenDate returns the parsed @last_seen date of an Email
+func (v Email) LastSeenDate() (Date, error) {
+	return ParseDate(v.LastSeen)
+}
+
+// ValidSinceDate returns the parsed @valid_since date of a Username
+func (v Username) ValidSinceDate() (Date, error) {
+	return ParseDate(v.ValidSince)
+}
+
+// LastSeenDate returns the parsed @last_seen date of a Username
+func (v Username) LastSeenDate() (Date, error) {
+	return ParseDate(v.LastSeen)
+}
+
+// ValidSinceDate returns the parsed @valid_since date of a UserID
+func (v UserID) ValidSinceDate() (Date, error) {
+	return ParseDate(v.ValidSince)
+}
+
+// LastSeenDate returns the parsed @last_seen date of a UserID
+func (v UserID) LastSeenDate() (Date, error) {
+	return ParseDate(v.LastSeen)
+}
+
+// ValidSinceDate returns the parsed @valid_since date of a DateRange
+func (v DateRange) ValidSinceDate() (Date, error) {
+	return ParseDate(v.ValidSince)
+}
+
+// LastSeenDate returns the parsed @last_seen date of a DateRange
+func (v DateRange) LastSeenDate() (Date, error) {
+	return ParseDate(v.LastSeen)
+}
+
+// ValidSinceDate returns the parsed @valid_since date of a DateOfBirth
+func (v DateOfBirth) ValidSinceDate() (Date, error) {
+	return ParseDate(v.ValidSince)
+}
+
+// LastSeenDate returns the parsed @last_seen date of a DateOfBirth
+func (v DateOfBirth) LastSeenDate() (Date, error) {
+	return ParseDate(v.LastSeen)
+}
+
+// ValidSinceDate returns the parsed @valid_since date of an Image
+func (v Image) ValidSinceDate() (Date, error) {
+	return ParseDate(v.ValidSince)
+}
+
+// LastSeenDate returns the parsed @last_seen date of an Image
+func (v Image) LastSeenDate() (Date, error) {
+	return ParseDate(v.LastSeen)
+}
+
+// ValidSinceDate returns the parsed @valid_since date of a Job
+func (v Job) ValidSinceDate() (Date, error) {
+	return ParseDate(v.ValidSince)
+}
+
+// LastSeenDate returns the parsed @last_seen date of a Job
+func (v Job) LastSeenDate() (Date, error) {
+	return ParseDate(v.LastSeen)
+}
+
+// ValidSinceDate returns the parsed @valid_since date of an Education
+func (v Education) ValidSinceDate() (Date, error) {
+	return ParseDate(v.ValidSince)
+}
+
+// LastSeenDate returns the parsed @last_seen date of an Education
+func (v Education) LastSeenDate() (Date, error) {
+	return ParseDate(v.LastSeen)
+}
+
+// ValidSinceDate returns the parsed @valid_since date of a Gender
+func (v Gender) ValidSinceDate() (Date, error) {
+	return ParseDate(v.ValidSince)
+}
+
+// LastSeenDate returns the parsed @last_seen date of a Gender
+func (v Gender) LastSeenDate() (Date, error) {
+	return ParseDate(v.LastSeen)
+}
+
+// ValidSinceDate returns the parsed @valid_since date of an Ethnicity
+func (v Ethnicity) ValidSinceDate() (Date, error) {
+	return ParseDate(v.ValidSince)
+}
+
+// LastSeenDate returns the parsed @last_seen date of an Ethnicity
+func (v Ethnicity) LastSeenDate() (Date, error) {
+	return ParseDate(v.LastSeen)
+}
+
+// ValidSinceDate returns the parsed @valid_since date of a Language
+func (v Language) ValidSinceDate() (Date, error) {
+	return ParseDate(v.ValidSince)
+}
+
+// LastSeenDate returns the parsed @last_seen date of a Language
+func (v Language) LastSeenDate() (Date, error) {
+	return ParseDate(v.LastSeen)
+}
+
+// ValidSinceDate returns the parsed @valid_since date of an OriginCountry
+func (v OriginCountry) ValidSinceDate() (Date, error) {
+	return ParseDate(v.ValidSince)
+}
+
+// LastSeenDate returns the parsed @last_seen date of an OriginCountry
+func (v OriginCountry) LastSeenDate() (Date, error) {
+	return ParseDate(v.LastSeen)
+}
+
+// ValidSinceDate returns the parsed @valid_since date of a Relationship
+func (v Relationship) ValidSinceDate() (Date, error) {
+	return ParseDate(v.ValidSince)
+}
+
+// LastSeenDate returns the parsed @last_seen date of a Relationship
+func (v Relationship) LastSeenDate() (Date, error) {
+	return ParseDate(v.LastSeen)
+}
+
+// ValidSinceDate returns the parsed @valid_since date of a URL
+func (v URL) ValidSinceDate() (Date, error) {
+	return ParseDate(v.ValidSince)
+}
+
+// LastSeenDate returns the parsed @last_seen date of a URL
+func (v URL) LastSeenDate() (Date, error) {
+	return ParseDate(v.LastSeen)
+}
+
+// ValidSinceDate returns the parsed @valid_since date of a Vehicle
+func (v Vehicle) ValidSinceDate() (Date, error) {
+	return ParseDate(v.ValidSince)
+}
+
+// LastSeenDate returns the parsed @last_seen date of a Vehicle
+func (v Vehicle) LastSeenDate() (Date, error) {
+	return ParseDate(v.LastSeen)
+}
+
+// BirthDate returns the date of birth when the range is a single day, month or year
+// (for example "1986-02-01" to "1986-02-28" is "1986-02"), or a zero Date
+func (v DateOfBirth) BirthDate() Date {
+	start, err := ParseDate(v.DateRange.Start)
+	if err != nil || start.IsZero() {
+		return Date{}
+	}
+	end, err := ParseDate(cmp.Or(v.DateRange.End, v.DateRange.Start))
+	if err != nil {
+		return Date{}
+	}
+
+	for _, precision := range []DatePrecision{DatePrecisionDay, DatePrecisionMonth, DatePrecisionYear} {
+		period := Date{Time: truncateDate(start.Time, precision), Precision: precision}
+		if period.Time.Equal(start.Time) && period.End().Equal(end.End()) {
+			return period
+		}
+	}
+	return Date{}
+}
+
+// truncateDate returns the start of the day, month or year of the time
+func truncateDate(t time.Time, precision DatePrecision) time.Time {
+	switch precision {
+	case DatePrecisionYear:
+		return time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	case DatePrecisionMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	case DatePrecisionNone, DatePrecisionDay, DatePrecisionSecond:
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
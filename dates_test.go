@@ -0,0 +1,181 @@
+package pipl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseDate will test the method ParseDate()
+func TestParseDate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		value     string
+		expected  time.Time
+		precision DatePrecision
+		end       time.Time
+		wantErr   bool
+	}{
+		{"empty", "", time.Time{}, DatePrecisionNone, time.Time{}, false},
+		{"year", "2010", time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC), DatePrecisionYear, time.Date(2010, 12, 31, 23, 59, 59, 999999999, time.UTC), false},
+		{"month", "2010-02", time.Date(2010, 2, 1, 0, 0, 0, 0, time.UTC), DatePrecisionMonth, time.Date(2010, 2, 28, 23, 59, 59, 999999999, time.UTC), false},
+		{"day", " 2010-02-03 ", time.Date(2010, 2, 3, 0, 0, 0, 0, time.UTC), DatePrecisionDay, time.Date(2010, 2, 3, 23, 59, 59, 999999999, time.UTC), false},
+		{"timestamp", "2010-02-03 04:05:06", time.Date(2010, 2, 3, 4, 5, 6, 0, time.UTC), DatePrecisionSecond, time.Date(2010, 2, 3, 4, 5, 6, 0, time.UTC), false},
+		{"rfc3339", "2010-02-03T04:05:06Z", time.Date(2010, 2, 3, 4, 5, 6, 0, time.UTC), DatePrecisionSecond, time.Date(2010, 2, 3, 4, 5, 6, 0, time.UTC), false},
+		{"invalid month", "2010-13", time.Time{}, DatePrecisionNone, time.Time{}, true},
+		{"invalid day", "2010-02-30", time.Time{}, DatePrecisionNone, time.Time{}, true},
+		{"garbage", "last tuesday", time.Time{}, DatePrecisionNone, time.Time{}, true},
+		{"short year", "10", time.Time{}, DatePrecisionNone, time.Time{}, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			date, err := ParseDate(test.value)
+			if test.wantErr {
+				require.ErrorIs(t, err, ErrInvalidDate)
+				assert.True(t, date.IsZero())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, date.Time)
+			assert.Equal(t, test.precision, date.Precision)
+			assert.Equal(t, test.end, date.End())
+		})
+	}
+}
+
+// TestDate_String will test the method String()
+func TestDate_String(t *testing.T) {
+	t.Parallel()
+
+	for _, value := range []string{"", "2010", "2010-02", "2010-02-03", "2010-02-03T04:05:06Z"} {
+		date, err := ParseDate(value)
+		require.NoError(t, err)
+		assert.Equal(t, value, date.String())
+	}
+	assert.Equal(t, "month", DatePrecisionMonth.String())
+	assert.Equal(t, "DatePrecision(99)", DatePrecision(99).String())
+}
+
+// TestDated will test the date methods of the field types
+func TestDated(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid since and last seen", func(t *testing.T) {
+		email := Email{ValidSince: "2012", LastSeen: "2019-06-10"}
+		validSince, err := email.ValidSinceDate()
+		require.NoError(t, err)
+		assert.Equal(t, DatePrecisionYear, validSince.Precision)
+
+		var lastSeen Date
+		lastSeen, err = email.LastSeenDate()
+		require.NoError(t, err)
+		assert.Equal(t, DatePrecisionDay, lastSeen.Precision)
+
+		var recency Date
+		recency, err = Recency(email)
+		require.NoError(t, err)
+		assert.Equal(t, lastSeen, recency)
+	})
+
+	t.Run("recency falls back to valid since", func(t *testing.T) {
+		recency, err := Recency(Address{ValidSince: "2005-02"})
+		require.NoError(t, err)
+		assert.Equal(t, "2005-02", recency.String())
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		_, err := Recency(Phone{LastSeen: "yesterday"})
+		require.ErrorIs(t, err, ErrInvalidDate)
+	})
+
+	t.Run("date range", func(t *testing.T) {
+		job := Job{DateRange: DateRange{Start: "2000-12", End: "2012-10-09"}}
+		start, err := job.DateRange.StartDate()
+		require.NoError(t, err)
+		assert.Equal(t, DatePrecisionMonth, start.Precision)
+
+		var end Date
+		end, err = job.DateRange.EndDate()
+		require.NoError(t, err)
+		assert.Equal(t, DatePrecisionDay, end.Precision)
+	})
+}
+
+// TestSortByRecency will test the method SortByRecency()
+func TestSortByRecency(t *testing.T) {
+	t.Parallel()
+
+	emails := []Email{
+		{Address: "old@example.com", ValidSince: "2001"},
+		{Address: "bad@example.com", LastSeen: "not-a-date"},
+		{Address: "new@example.com", LastSeen: "2019-06-10"},
+		{Address: "none@example.com"},
+		{Address: "mid@example.com", ValidSince: "2015-03"},
+	}
+	SortByRecency(emails)
+
+	addresses := make([]string, 0, len(emails))
+	for _, email := range emails {
+		addresses = append(addresses, email.Address)
+	}
+	assert.Equal(t, []string{"new@example.com", "mid@example.com", "old@example.com", "bad@example.com", "none@example.com"}, addresses)
+}
+
+// TestFilterSince will test the method FilterSince()
+func TestFilterSince(t *testing.T) {
+	t.Parallel()
+
+	phones := []Phone{
+		{Raw: "1", ValidSince: "2001"},
+		{Raw: "2", LastSeen: "2015"},
+		{Raw: "3", LastSeen: "2016-01-02"},
+		{Raw: "4", LastSeen: "bad"},
+		{Raw: "5"},
+	}
+
+	filtered := FilterSince(phones, time.Date(2015, 6, 1, 0, 0, 0, 0, time.UTC))
+	require.Len(t, filtered, 2)
+	assert.Equal(t, "2", filtered[0].Raw)
+	assert.Equal(t, "3", filtered[1].Raw)
+}
+
+// TestMostRecent will test the method MostRecent()
+func TestMostRecent(t *testing.T) {
+	t.Parallel()
+
+	job, ok := MostRecent([]Job{{Title: "a", ValidSince: "2001"}, {Title: "b", LastSeen: "2010-01"}, {Title: "c"}})
+	require.True(t, ok)
+	assert.Equal(t, "b", job.Title)
+
+	_, ok = MostRecent([]Job{{Title: "a"}})
+	assert.False(t, ok)
+}
+
+// TestDateOfBirth_BirthDate will test the method BirthDate()
+func TestDateOfBirth_BirthDate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		start    string
+		end      string
+		expected string
+	}{
+		{"1986-06-18", "1986-06-18", "1986-06-18"},
+		{"1986-06-18", "", "1986-06-18"},
+		{"1986-02-01", "1986-02-28", "1986-02"},
+		{"1986-01-01", "1986-12-31", "1986"},
+		{"1986", "1986", "1986"},
+		{"1985-01-01", "1986-12-31", ""},
+		{"1986-02-02", "1986-02-28", ""},
+		{"bad", "1986-02-28", ""},
+		{"", "", ""},
+	}
+	for _, test := range tests {
+		dob := DateOfBirth{DateRange: DateRange{Start: test.start, End: test.end}}
+		assert.Equal(t, test.expected, dob.BirthDate().String(), test.start+" to "+test.end)
+	}
+}
@@ -0,0 +1,207 @@
+package pipl
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DecodeMode is how unknown (unrecognized) JSON fields are handled when decoding
+type DecodeMode int
+
+const (
+	// DecodeModeDefault drops unknown fields (standard encoding/json behavior)
+	DecodeModeDefault DecodeMode = iota
+
+	// DecodeModePreserve keeps the unknown fields of Response, Person and Source in their Extra
+	// field, and re-emits them when encoding (so stored copies do not lose new API fields)
+	DecodeModePreserve
+
+	// DecodeModeStrict preserves the unknown fields (like DecodeModePreserve), and reports every
+	// unknown field (at any depth) and undocumented typed value (for example, a phone @type)
+	// with an UnknownFieldsError, to detect schema drift
+	DecodeModeStrict
+)
+
+// ExtraFields are the unknown (unrecognized) JSON fields of a struct, by key
+type ExtraFields map[string]json.RawMessage
+
+// jsonFields is a cache of the JSON field names of a struct type (name -> field index)
+var jsonFields sync.Map //nolint:gochecknoglobals // Cache of reflected struct fields
+
+// extraType is the type of the Extra field (unknown fields)
+var extraType = reflect.TypeOf(ExtraFields(nil)) //nolint:gochecknoglobals // Reflected type
+
+// Decode will decode the JSON data into v (for example, a *Response) using the decode mode.
+//
+// In DecodeModeStrict the data is fully decoded, and an *UnknownFieldsError is returned if
+// there are any unknown fields, or unknown values of a Response, Person or Source (the decoded
+// value can still be used).
+func Decode(data []byte, v any, mode DecodeMode) error {
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+	if mode == DecodeModeDefault {
+		return nil
+	}
+
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Pointer || value.IsNil() {
+		return nil
+	}
+
+	var unknown []string
+	collectUnknownFields(data, value.Elem(), "", &unknown)
+	if mode != DecodeModeStrict {
+		return nil
+	}
+
+	// Undocumented typed values (they are kept, like unknown fields)
+	var values []UnknownValue
+	if valuer, ok := v.(unknownValuer); ok {
+		values = valuer.UnknownValues()
+	}
+	if len(unknown) > 0 || len(values) > 0 {
+		return &UnknownFieldsError{Fields: unknown, Values: values}
+	}
+	return nil
+}
+
+// unknownValuer is a decoded value that can report its unknown values (Response, Person and Source)
+type unknownValuer interface {
+	UnknownValues() []UnknownValue
+}
+
+// MarshalJSON will encode the response, including any preserved unknown fields
+func (r Response) MarshalJSON() ([]byte, error) {
+	type response Response
+	return marshalWithExtra(response(r), r.Extra)
+}
+
+// MarshalJSON will encode the person, including any preserved unknown fields
+func (p Person) MarshalJSON() ([]byte, error) {
+	type person Person
+	return marshalWithExtra(person(p), p.Extra)
+}
+
+// MarshalJSON will encode the source, including any preserved unknown fields
+func (s Source) MarshalJSON() ([]byte, error) {
+	type source Source
+	return marshalWithExtra(source(s), s.Extra)
+}
+
+// marshalWithExtra will encode the value and add the extra (unknown) fields, known fields always win
+func marshalWithExtra(v any, extra ExtraFields) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(extra) == 0 {
+		return data, err
+	}
+
+	fields := make(map[string]json.RawMessage)
+	if err = json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for key, value := range extra {
+		if _, ok := fields[key]; !ok {
+			fields[key] = value
+		}
+	}
+	return json.Marshal(fields)
+}
+
+// collectUnknownFields walks the JSON data alongside the decoded value, collecting the paths
+// of unknown fields, and keeping them in the Extra field (if the struct has one)
+func collectUnknownFields(data json.RawMessage, value reflect.Value, path string, unknown *[]string) {
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return
+	}
+
+	switch value.Kind() { //nolint:exhaustive // Only containers are walked
+	case reflect.Pointer:
+		if !value.IsNil() {
+			collectUnknownFields(data, value.Elem(), path, unknown)
+		}
+	case reflect.Slice, reflect.Array:
+		var items []json.RawMessage
+		if json.Unmarshal(data, &items) != nil {
+			return
+		}
+		for index := 0; index < len(items) && index < value.Len(); index++ {
+			collectUnknownFields(items[index], value.Index(index), path+"["+strconv.Itoa(index)+"]", unknown)
+		}
+	case reflect.Struct:
+		var fields map[string]json.RawMessage
+		if json.Unmarshal(data, &fields) != nil {
+			return
+		}
+
+		known := structJSONFields(value.Type())
+		keys := make([]string, 0, len(fields))
+		for key := range fields {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+
+		extra := make(ExtraFields)
+		for _, key := range keys {
+			fieldPath := key
+			if len(path) > 0 {
+				fieldPath = path + "." + key
+			}
+			if index, ok := lookupJSONField(known, key); ok {
+				collectUnknownFields(fields[key], value.Field(index), fieldPath, unknown)
+				continue
+			}
+			*unknown = append(*unknown, fieldPath)
+			extra[key] = fields[key]
+		}
+
+		// Keep the unknown fields (only structs with an Extra field)
+		if field := value.FieldByName("Extra"); len(extra) > 0 && field.IsValid() &&
+			field.Type() == extraType && field.CanSet() {
+			field.Set(reflect.ValueOf(extra))
+		}
+	}
+}
+
+// structJSONFields returns the JSON field names of the struct type (cached)
+func structJSONFields(structType reflect.Type) map[string]int {
+	if fields, ok := jsonFields.Load(structType); ok {
+		return fields.(map[string]int) //nolint:errcheck,forcetypeassert // Only maps are stored
+	}
+
+	fields := make(map[string]int, structType.NumField())
+	for index := 0; index < structType.NumField(); index++ {
+		field := structType.Field(index)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if len(name) == 0 {
+			name = field.Name
+		}
+		fields[name] = index
+	}
+	jsonFields.Store(structType, fields)
+	return fields
+}
+
+// lookupJSONField finds the field for the JSON key (exact match first, then case-insensitive like encoding/json)
+func lookupJSONField(fields map[string]int, key string) (int, bool) {
+	if index, ok := fields[key]; ok {
+		return index, true
+	}
+	for name, index := range fields {
+		if strings.EqualFold(name, key) {
+			return index, true
+		}
+	}
+	return 0, false
+}
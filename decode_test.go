@@ -0,0 +1,165 @@
+package pipl
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testUnknownFieldsJSON is a response with unknown fields at every level
+const testUnknownFieldsJSON = `{
+	"@http_status_code": 200,
+	"@persons_count": 1,
+	"@new_counter": 7,
+	"person": {
+		"@id": "f4a7d898-6fc1-4a24-b043-43eb292a6fd5",
+		"@risk_score": 0.4,
+		"names": [{"display": "Clark Kent", "nickname": "Smallville"}]
+	},
+	"possible_persons": [{"@match": 0.5, "pets": [{"name": "Krypto"}]}],
+	"sources": [{"@id": "s1", "@confidence": "high"}]
+}`
+
+// TestDecode will test decoding with the different decode modes
+func TestDecode(t *testing.T) {
+	t.Parallel()
+
+	t.Run("default mode drops unknown fields", func(t *testing.T) {
+		response := new(Response)
+		require.NoError(t, Decode([]byte(testUnknownFieldsJSON), response, DecodeModeDefault))
+		assert.Nil(t, response.Extra)
+		assert.Nil(t, response.Person.Extra)
+		assert.Equal(t, "Clark Kent", response.Person.Names[0].Display)
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		response := new(Response)
+		require.Error(t, Decode([]byte(`{error:bad-json}`), response, DecodeModeStrict))
+	})
+
+	t.Run("preserve mode keeps unknown fields", func(t *testing.T) {
+		response := new(Response)
+		require.NoError(t, Decode([]byte(testUnknownFieldsJSON), response, DecodeModePreserve))
+
+		assert.JSONEq(t, `7`, string(response.Extra["@new_counter"]))
+		assert.JSONEq(t, `0.4`, string(response.Person.Extra["@risk_score"]))
+		assert.JSONEq(t, `[{"name": "Krypto"}]`, string(response.PossiblePersons[0].Extra["pets"]))
+		assert.JSONEq(t, `"high"`, string(response.Sources[0].Extra["@confidence"]))
+		assert.InDelta(t, 0.5, response.PossiblePersons[0].Match, 0.001)
+	})
+
+	t.Run("preserved fields are re-emitted", func(t *testing.T) {
+		response := new(Response)
+		require.NoError(t, Decode([]byte(testUnknownFieldsJSON), response, DecodeModePreserve))
+
+		data, err := json.Marshal(response)
+		require.NoError(t, err)
+
+		decoded := new(Response)
+		require.NoError(t, Decode(data, decoded, DecodeModePreserve))
+		assert.JSONEq(t, `7`, string(decoded.Extra["@new_counter"]))
+		assert.JSONEq(t, `0.4`, string(decoded.Person.Extra["@risk_score"]))
+		assert.JSONEq(t, `[{"name": "Krypto"}]`, string(decoded.PossiblePersons[0].Extra["pets"]))
+		assert.JSONEq(t, `"high"`, string(decoded.Sources[0].Extra["@confidence"]))
+	})
+
+	t.Run("known fields win over extra fields", func(t *testing.T) {
+		person := Person{ID: "abc", Extra: ExtraFields{"@id": json.RawMessage(`"xyz"`)}}
+		data, err := json.Marshal(person)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"@id": "abc"}`, string(data))
+	})
+
+	t.Run("strict mode reports unknown fields", func(t *testing.T) {
+		response := new(Response)
+		err := Decode([]byte(testUnknownFieldsJSON), response, DecodeModeStrict)
+		require.ErrorIs(t, err, ErrUnknownFields)
+
+		var unknown *UnknownFieldsError
+		require.ErrorAs(t, err, &unknown)
+		assert.Equal(t, []string{
+			"@new_counter",
+			"person.@risk_score",
+			"person.names[0].nickname",
+			"possible_persons[0].pets",
+			"sources[0].@confidence",
+		}, unknown.Fields)
+
+		// Still decoded (and preserved)
+		assert.Equal(t, "Clark Kent", response.Person.Names[0].Display)
+		assert.NotNil(t, response.Person.Extra)
+	})
+
+	t.Run("strict mode reports unknown values", func(t *testing.T) {
+		person := new(Person)
+		err := Decode([]byte(`{"phones": [{"@type": "mobile"}, {"@type": "satellite"}]}`), person, DecodeModeStrict)
+		require.ErrorIs(t, err, ErrUnknownFields)
+		assert.Equal(t, `unknown fields: person.phones[1].@type="satellite"`, err.Error())
+
+		var unknown *UnknownFieldsError
+		require.ErrorAs(t, err, &unknown)
+		assert.Empty(t, unknown.Fields)
+		assert.Equal(t, []UnknownValue{{Path: "person.phones[1].@type", Value: "satellite"}}, unknown.Values)
+
+		// Still decoded (the value is kept)
+		assert.Equal(t, PhoneType("satellite"), person.Phones[1].Type)
+
+		// Other modes do not report values
+		require.NoError(t, Decode([]byte(`{"phones": [{"@type": "satellite"}]}`), new(Person), DecodeModePreserve))
+	})
+
+	t.Run("fixtures have no unknown fields", func(t *testing.T) {
+		files, err := filepath.Glob("responses/*.json")
+		require.NoError(t, err)
+		require.NotEmpty(t, files)
+
+		for _, file := range files {
+			data, readErr := os.ReadFile(file) //nolint:gosec // Safe test file inclusion
+			require.NoError(t, readErr)
+			require.NoError(t, Decode(data, new(Response), DecodeModeStrict), file)
+		}
+	})
+}
+
+// TestClient_DecodeMode will test the decode mode of the client
+func TestClient_DecodeMode(t *testing.T) {
+	t.Parallel()
+
+	t.Run("strict mode returns the response with the unknown fields", func(t *testing.T) {
+		c := NewClient(WithAPIKey(testKey), WithHTTPClient(&rawResponse{body: testUnknownFieldsJSON}),
+			WithDecodeMode(DecodeModeStrict))
+		response, err := c.SearchByPointer(context.Background(), testSearchPointer)
+		require.ErrorIs(t, err, ErrUnknownFields)
+		require.NotNil(t, response)
+		assert.Equal(t, "Clark Kent", response.Person.Names[0].Display)
+		assert.Contains(t, response.Person.Extra, "@risk_score")
+
+		response, err = c.SearchAllPossiblePeople(context.Background(), &Person{Emails: []Email{{Address: "clark.kent@example.com"}}})
+		require.ErrorIs(t, err, ErrUnknownFields)
+		require.NotNil(t, response)
+		assert.Equal(t, 1, response.PersonsCount)
+	})
+
+	t.Run("strict mode reports the api error first", func(t *testing.T) {
+		c := NewClient(WithAPIKey(testKey), WithDecodeMode(DecodeModeStrict), WithHTTPClient(&rawResponse{
+			body: `{"@http_status_code": 400, "@new_counter": 7, "error": "search failed"}`,
+		}))
+		response, err := c.SearchByPointer(context.Background(), testSearchPointer)
+		require.ErrorIs(t, err, ErrAPIResponse)
+		require.NotErrorIs(t, err, ErrUnknownFields)
+		assert.Nil(t, response)
+	})
+
+	t.Run("preserve mode keeps the fields", func(t *testing.T) {
+		c := NewClient(WithAPIKey(testKey), WithHTTPClient(&rawResponse{body: testUnknownFieldsJSON}),
+			WithDecodeMode(DecodeModePreserve))
+		response, err := c.SearchByPointer(context.Background(), testSearchPointer)
+		require.NoError(t, err)
+		assert.Contains(t, response.Person.Extra, "@risk_score")
+	})
+}
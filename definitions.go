@@ -37,7 +37,7 @@ const (
 
 	// https://docs.pipl.com/reference#match-criteria
 
-	// MinimumProbability is the score for probability
+	// MinimumProbability is the score for probability (API default, only sent if different)
 	MinimumProbability = 0.9
 
 	// MinimumMatch is the minimum for a match
@@ -58,6 +58,9 @@ const (
 	// DefaultCountry is the default country for address
 	DefaultCountry string = "US"
 
+	// DefaultCountryCode is the country calling code of the DefaultCountry (for national phone numbers)
+	DefaultCountryCode int = 1
+
 	// DefaultLanguage is the default language
 	DefaultLanguage string = "en"
 
@@ -71,6 +74,7 @@ const (
 	fieldLiveFeeds                  = "live_feeds"
 	fieldMatchRequirements          = "match_requirements"
 	fieldMinimumMatch               = "minimum_match"
+	fieldMinimumProbability         = "minimum_probability"
 	fieldPerson                     = "person"
 	fieldPretty                     = "pretty"
 	fieldSearchPointer              = "search_pointer"
@@ -163,6 +167,18 @@ type ThumbnailSettings struct {
 	ZoomFace bool
 }
 
+// PossiblePersonsSettings controls how possible persons are expanded (by search pointer)
+// in SearchAllPossiblePeople(). Every expansion is a billed query.
+//
+// DO NOT CHANGE ORDER - Optimized for memory (malign)
+type PossiblePersonsSettings struct {
+	// Concurrency is the maximum number of search pointers resolved at once (default: DefaultBatchConcurrency)
+	Concurrency int
+
+	// MaxExpansions is the maximum number of possible persons to expand (0 is all), the rest are left as previews
+	MaxExpansions int
+}
+
 // GUID is a unique format (but is just a string internally, since there's currently
 // nothing all that fancy done with GUIDs). Additional guid-handling code may be
 // added at a later date if needed.
@@ -196,21 +212,21 @@ type Name struct {
 //
 // Source: https://docs.pipl.com/reference#address
 type Address struct {
-	Apartment  string `json:"apartment,omitempty"`
-	City       string `json:"city,omitempty"`
-	Country    string `json:"country,omitempty"`
-	Display    string `json:"display,omitempty"`
-	House      string `json:"house,omitempty"`
-	LastSeen   string `json:"@last_seen,omitempty"`
-	POBox      string `json:"po_box,omitempty"`
-	Raw        string `json:"raw,omitempty"`
-	State      string `json:"state,omitempty"`
-	Street     string `json:"street,omitempty"`
-	Type       string `json:"@type,omitempty"`
-	ValidSince string `json:"@valid_since,omitempty"`
-	ZipCode    string `json:"zip_code,omitempty"`
-	Current    bool   `json:"@current,omitempty"`
-	Inferred   bool   `json:"@inferred,omitempty"`
+	Apartment  string      `json:"apartment,omitempty"`
+	City       string      `json:"city,omitempty"`
+	Country    string      `json:"country,omitempty"`
+	Display    string      `json:"display,omitempty"`
+	House      string      `json:"house,omitempty"`
+	LastSeen   string      `json:"@last_seen,omitempty"`
+	POBox      string      `json:"po_box,omitempty"`
+	Raw        string      `json:"raw,omitempty"`
+	State      string      `json:"state,omitempty"`
+	Street     string      `json:"street,omitempty"`
+	Type       AddressType `json:"@type,omitempty"`
+	ValidSince string      `json:"@valid_since,omitempty"`
+	ZipCode    string      `json:"zip_code,omitempty"`
+	Current    bool        `json:"@current,omitempty"`
+	Inferred   bool        `json:"@inferred,omitempty"`
 }
 
 // Phone fields collectively define a possible phone number for a given person
@@ -220,17 +236,19 @@ type Address struct {
 //
 // Source: https://docs.pipl.com/reference#phone
 type Phone struct {
-	Display              string `json:"display,omitempty"`
-	DisplayInternational string `json:"display_international,omitempty"`
-	LastSeen             string `json:"@last_seen,omitempty"`
-	Raw                  string `json:"raw,omitempty"`
-	Type                 string `json:"@type,omitempty"`
-	ValidSince           string `json:"@valid_since,omitempty"`
-	CountryCode          int    `json:"country_code,omitempty"`
-	Extension            int    `json:"extension,omitempty"`
-	Number               int64  `json:"number,omitempty"`
-	Current              bool   `json:"@current,omitempty"`
-	Inferred             bool   `json:"@inferred,omitempty"`
+	Display              string    `json:"display,omitempty"`
+	DisplayInternational string    `json:"display_international,omitempty"`
+	LastSeen             string    `json:"@last_seen,omitempty"`
+	Raw                  string    `json:"raw,omitempty"`
+	Type                 PhoneType `json:"@type,omitempty"`
+	ValidSince           string    `json:"@valid_since,omitempty"`
+	CountryCode          int       `json:"country_code,omitempty"`
+	Extension            int       `json:"extension,omitempty"`
+	Number               int64     `json:"number,omitempty"`
+	Current              bool      `json:"@current,omitempty"`
+	DoNotCall            bool      `json:"@do_not_call,omitempty"`
+	Inferred             bool      `json:"@inferred,omitempty"`
+	VoIP                 bool      `json:"@voip,omitempty"`
 }
 
 // Email fields collectively define a possible email address for a given person
@@ -238,15 +256,16 @@ type Phone struct {
 //
 // Source: https://docs.pipl.com/reference#email
 type Email struct {
-	Address       string `json:"address,omitempty"`
-	AddressMD5    string `json:"address_md5,omitempty"`
-	Current       bool   `json:"@current,omitempty"`
-	Disposable    bool   `json:"@disposable,omitempty"`
-	EmailProvider bool   `json:"@email_provider,omitempty"`
-	Inferred      bool   `json:"@inferred,omitempty"`
-	LastSeen      string `json:"@last_seen,omitempty"`
-	Type          string `json:"@type,omitempty"`
-	ValidSince    string `json:"@valid_since,omitempty"`
+	Address       string    `json:"address,omitempty"`
+	AddressMD5    string    `json:"address_md5,omitempty"`
+	Current       bool      `json:"@current,omitempty"`
+	Deliverable   bool      `json:"@deliverable,omitempty"`
+	Disposable    bool      `json:"@disposable,omitempty"`
+	EmailProvider bool      `json:"@email_provider,omitempty"`
+	Inferred      bool      `json:"@inferred,omitempty"`
+	LastSeen      string    `json:"@last_seen,omitempty"`
+	Type          EmailType `json:"@type,omitempty"`
+	ValidSince    string    `json:"@valid_since,omitempty"`
 }
 
 // Username fields collectively define a possible username used by a given person.
@@ -486,37 +505,60 @@ type OriginCountry struct {
 	ValidSince string `json:"@valid_since,omitempty"`
 }
 
+// Vehicle contains information about a vehicle registered to (or associated with) the given person.
+//
+// DO NOT CHANGE ORDER - Optimized for memory (malign)
+//
+// Source: https://docs.pipl.com/reference#vehicle
+type Vehicle struct {
+	Color      string `json:"color,omitempty"`
+	Display    string `json:"display,omitempty"`
+	LastSeen   string `json:"@last_seen,omitempty"`
+	Make       string `json:"make,omitempty"`
+	Model      string `json:"model,omitempty"`
+	Type       string `json:"vehicle_type,omitempty"`
+	ValidSince string `json:"@valid_since,omitempty"`
+	VIN        string `json:"vin,omitempty"`
+	Year       int    `json:"year,omitempty"`
+	Current    bool   `json:"@current,omitempty"`
+	Inferred   bool   `json:"@inferred,omitempty"`
+}
+
 // Relationship contains information about a person who is closely related to
 // the person being searched. This can be family members, spouses, children, etc.
 // Type  and Subtype contain information about the nature of the relationship to
 // the person being searched. For example, Type = "Family", Subtype = "Father".
-// Type can be one of: "work", "family", "friend" (default), "other"
+// Type can be one of: "work", "family", "friend" (default), "other" (see RelationshipType).
+// Subtype is free text (for example "Father"), and is not validated.
+// SearchPointer (if returned) can be used to get the full profile of the related person.
 //
 // DO NOT CHANGE ORDER - Optimized for memory (malign)
 //
 // Source: https://docs.pipl.com/reference#relationship
 type Relationship struct {
-	DateOfBirth     DateOfBirth     `json:"dob,omitempty"`
-	Gender          Gender          `json:"gender,omitempty"`
-	Addresses       []Address       `json:"addresses,omitempty"`
-	Educations      []Education     `json:"educations,omitempty"`
-	Emails          []Email         `json:"emails,omitempty"`
-	Ethnicities     []Ethnicity     `json:"ethnicities,omitempty"`
-	Images          []Image         `json:"images,omitempty"`
-	Jobs            []Job           `json:"jobs,omitempty"`
-	Languages       []Language      `json:"languages,omitempty"`
-	Names           []Name          `json:"names,omitempty"`
-	OriginCountries []OriginCountry `json:"origin_countries,omitempty"`
-	Phones          []Phone         `json:"phones,omitempty"`
-	Relationships   []Relationship  `json:"relationships,omitempty"`
-	UserIDs         []UserID        `json:"user_ids,omitempty"`
-	Usernames       []Username      `json:"usernames,omitempty"`
-	LastSeen        string          `json:"@last_seen,omitempty"`
-	Subtype         string          `json:"@subtype,omitempty"`
-	Type            string          `json:"@type,omitempty"`
-	ValidSince      string          `json:"@valid_since,omitempty"`
-	Current         bool            `json:"@current,omitempty"`
-	Inferred        bool            `json:"@inferred,omitempty"`
+	DateOfBirth     DateOfBirth      `json:"dob,omitempty"`
+	Gender          Gender           `json:"gender,omitempty"`
+	Addresses       []Address        `json:"addresses,omitempty"`
+	Educations      []Education      `json:"educations,omitempty"`
+	Emails          []Email          `json:"emails,omitempty"`
+	Ethnicities     []Ethnicity      `json:"ethnicities,omitempty"`
+	Images          []Image          `json:"images,omitempty"`
+	Jobs            []Job            `json:"jobs,omitempty"`
+	Languages       []Language       `json:"languages,omitempty"`
+	Names           []Name           `json:"names,omitempty"`
+	OriginCountries []OriginCountry  `json:"origin_countries,omitempty"`
+	Phones          []Phone          `json:"phones,omitempty"`
+	Relationships   []Relationship   `json:"relationships,omitempty"`
+	URLs            []URL            `json:"urls,omitempty"`
+	UserIDs         []UserID         `json:"user_ids,omitempty"`
+	Usernames       []Username       `json:"usernames,omitempty"`
+	LastSeen        string           `json:"@last_seen,omitempty"`
+	SearchPointer   string           `json:"@search_pointer,omitempty"`
+	Subtype         string           `json:"@subtype,omitempty"`
+	Type            RelationshipType `json:"@type,omitempty"`
+	ValidSince      string           `json:"@valid_since,omitempty"`
+	Current         bool             `json:"@current,omitempty"`
+	Inferred        bool             `json:"@inferred,omitempty"`
 }
 
 // URL contains information about a URL that is closely associated with a given person.
@@ -525,23 +567,23 @@ type Relationship struct {
 //
 // Source: https://docs.pipl.com/reference#url
 type URL struct {
-	Category   string `json:"@category,omitempty"`
-	Domain     string `json:"@domain,omitempty"`
-	LastSeen   string `json:"@last_seen,omitempty"`
-	Name       string `json:"@name,omitempty"`
-	SourceID   string `json:"@source_id,omitempty"`
-	URL        string `json:"url,omitempty"`
-	ValidSince string `json:"@valid_since,omitempty"`
-	Current    bool   `json:"@current,omitempty"`
-	Inferred   bool   `json:"@inferred,omitempty"`
+	Category   SourceCategory `json:"@category,omitempty"`
+	Domain     string         `json:"@domain,omitempty"`
+	LastSeen   string         `json:"@last_seen,omitempty"`
+	Name       string         `json:"@name,omitempty"`
+	SourceID   string         `json:"@source_id,omitempty"`
+	URL        string         `json:"url,omitempty"`
+	ValidSince string         `json:"@valid_since,omitempty"`
+	Current    bool           `json:"@current,omitempty"`
+	Inferred   bool           `json:"@inferred,omitempty"`
 }
 
 // Tag contains content classification information
 //
 // Source: https://docs.pipl.com/reference#tag
 type Tag struct {
-	Classification string `json:"@classification,omitempty"`
-	Content        string `json:"content,omitempty"`
+	Classification TagClassification `json:"@classification,omitempty"`
+	Content        string            `json:"content,omitempty"`
 }
 
 // Person contains all the information pertaining to a possible person match,
@@ -568,6 +610,8 @@ type Person struct {
 	URLs            []URL           `json:"urls,omitempty"`
 	UserIDs         []UserID        `json:"user_ids,omitempty"`
 	Usernames       []Username      `json:"usernames,omitempty"`
+	Vehicles        []Vehicle       `json:"vehicles,omitempty"`
+	Extra           ExtraFields     `json:"-"`
 	ID              GUID            `json:"@id,omitempty"`
 	SearchPointer   string          `json:"@search_pointer,omitempty"`
 	DateOfBirth     *DateOfBirth    `json:"dob,omitempty"`
@@ -590,6 +634,7 @@ type Source struct {
 	Educations      []Education     `json:"educations"`
 	Emails          []Email         `json:"emails"`
 	Ethnicities     []Ethnicity     `json:"ethnicities"`
+	Images          []Image         `json:"images"`
 	Jobs            []Job           `json:"jobs"`
 	Languages       []Language      `json:"languages"`
 	Names           []Name          `json:"names"`
@@ -600,7 +645,9 @@ type Source struct {
 	URLs            []URL           `json:"urls"`
 	UserIDs         []UserID        `json:"user_ids"`
 	Usernames       []Username      `json:"usernames"`
-	Category        string          `json:"@category"`
+	Vehicles        []Vehicle       `json:"vehicles"`
+	Extra           ExtraFields     `json:"-"`
+	Category        SourceCategory  `json:"@category"`
 	Domain          string          `json:"@domain"`
 	ID              string          `json:"@id"`
 	Name            string          `json:"@name"`
@@ -633,6 +680,7 @@ type FieldCount struct {
 	SocialProfiles  int `json:"social_profiles"`
 	UserIDs         int `json:"user_ids"`
 	Usernames       int `json:"usernames"`
+	Vehicles        int `json:"vehicles"`
 }
 
 // AvailableData aggregates the counts for found attributes that are relevant to
@@ -654,6 +702,7 @@ type Response struct {
 	AvailableData     AvailableData `json:"available_data"`
 	AvailableSources  int           `json:"@available_sources"`
 	Error             string        `json:"error"`
+	Extra             ExtraFields   `json:"-"`
 	HTTPStatusCode    int           `json:"@http_status_code"`
 	MatchRequirements string        `json:"match_requirements"`
 	Person            Person        `json:"person"`
@@ -0,0 +1,222 @@
+package pipl
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// PersonDiff is the difference between two snapshots of the same person (see DiffPersons)
+//
+// DO NOT CHANGE ORDER - Optimized for memory (malign)
+type PersonDiff struct {
+	Fields []FieldDiff `json:"fields"` // Only the fields with changes
+	ID     GUID        `json:"id,omitempty"`
+}
+
+// FieldDiff is the added, removed and modified entries of a single field (for example, "emails")
+//
+// DO NOT CHANGE ORDER - Optimized for memory (malign)
+type FieldDiff struct {
+	Added    []DiffEntry        `json:"added,omitempty"`
+	Modified []DiffModification `json:"modified,omitempty"`
+	Removed  []DiffEntry        `json:"removed,omitempty"`
+	Field    string             `json:"field"`
+}
+
+// DiffEntry is an added or removed entry, by its normalized key
+type DiffEntry struct {
+	Value any    `json:"value"`
+	Key   string `json:"key"`
+}
+
+// DiffModification is an entry in both snapshots (same normalized key) with different attributes
+//
+// DO NOT CHANGE ORDER - Optimized for memory (malign)
+type DiffModification struct {
+	New     any      `json:"new"`
+	Old     any      `json:"old"`
+	Changed []string `json:"changed"` // JSON names of the changed attributes (for example, "@last_seen")
+	Key     string   `json:"key"`
+}
+
+// DiffPersons will compare two snapshots of the same person (for example, the same @id searched
+// a month apart) and return the added, removed and modified entries of every field.
+//
+// Entries are matched by their normalized value (E.164 phone, lowercase email, normalized
+// address, etc.), so formatting differences are not changes. Text attributes are compared
+// ignoring case and surrounding whitespace. A nil snapshot is an empty person.
+func DiffPersons(oldPerson, newPerson *Person) PersonDiff {
+	if oldPerson == nil {
+		oldPerson = new(Person)
+	}
+	if newPerson == nil {
+		newPerson = new(Person)
+	}
+
+	diff := PersonDiff{Fields: []FieldDiff{}, ID: newPerson.ID}
+	if len(diff.ID) == 0 {
+		diff.ID = oldPerson.ID
+	}
+
+	diff.add(diffField("addresses", oldPerson.Addresses, newPerson.Addresses, addressKey))
+	diff.add(diffField("dob", singleValue(oldPerson.DateOfBirth), singleValue(newPerson.DateOfBirth), dobKey))
+	diff.add(diffField("educations", oldPerson.Educations, newPerson.Educations, educationKey))
+	diff.add(diffField("emails", oldPerson.Emails, newPerson.Emails, emailKey))
+	diff.add(diffField("ethnicities", oldPerson.Ethnicities, newPerson.Ethnicities, ethnicityKey))
+	diff.add(diffField("gender", singleValue(oldPerson.Gender), singleValue(newPerson.Gender), genderKey))
+	diff.add(diffField("images", oldPerson.Images, newPerson.Images, imageKey))
+	diff.add(diffField("jobs", oldPerson.Jobs, newPerson.Jobs, jobKey))
+	diff.add(diffField("languages", oldPerson.Languages, newPerson.Languages, languageKey))
+	diff.add(diffField("names", oldPerson.Names, newPerson.Names, nameKey))
+	diff.add(diffField("origin_countries", oldPerson.OriginCountries, newPerson.OriginCountries, originCountryKey))
+	diff.add(diffField("phones", oldPerson.Phones, newPerson.Phones, phoneKey))
+	diff.add(diffField("relationships", oldPerson.Relationships, newPerson.Relationships, relationshipKey))
+	diff.add(diffField("urls", oldPerson.URLs, newPerson.URLs, urlKey))
+	diff.add(diffField("user_ids", oldPerson.UserIDs, newPerson.UserIDs, userIDKey))
+	diff.add(diffField("usernames", oldPerson.Usernames, newPerson.Usernames, usernameKey))
+	diff.add(diffField("vehicles", oldPerson.Vehicles, newPerson.Vehicles, vehicleKey))
+
+	return diff
+}
+
+// HasChanges returns true if anything was added, removed or modified
+func (d PersonDiff) HasChanges() bool {
+	return len(d.Fields) > 0
+}
+
+// String returns a readable summary of the changes, for example:
+//
+//	person f4a7d898: 1 added, 1 removed, 1 modified
+//	emails:
+//	  + clark@example.com
+//	  - kent@example.com
+//	  ~ superman@example.com (@last_seen)
+func (d PersonDiff) String() string {
+	var added, modified, removed int
+	for _, field := range d.Fields {
+		added += len(field.Added)
+		modified += len(field.Modified)
+		removed += len(field.Removed)
+	}
+
+	var b strings.Builder
+	b.WriteString("person")
+	if len(d.ID) > 0 {
+		b.WriteString(" " + string(d.ID))
+	}
+	if !d.HasChanges() {
+		b.WriteString(": no changes")
+		return b.String()
+	}
+	_, _ = fmt.Fprintf(&b, ": %d added, %d removed, %d modified", added, removed, modified)
+
+	for _, field := range d.Fields {
+		b.WriteString("\n" + field.Field + ":")
+		for _, entry := range field.Added {
+			b.WriteString("\n  + " + entry.Key)
+		}
+		for _, entry := range field.Removed {
+			b.WriteString("\n  - " + entry.Key)
+		}
+		for _, entry := range field.Modified {
+			b.WriteString("\n  ~ " + entry.Key + " (" + strings.Join(entry.Changed, ", ") + ")")
+		}
+	}
+	return b.String()
+}
+
+// add adds the field diff (if there are any changes)
+func (d *PersonDiff) add(field FieldDiff) {
+	if len(field.Added) > 0 || len(field.Modified) > 0 || len(field.Removed) > 0 {
+		d.Fields = append(d.Fields, field)
+	}
+}
+
+// diffField compares the old and new values of a field, matched by key (duplicates are compared once).
+// Values without a key are matched by their JSON encoding.
+func diffField[T any](field string, oldValues, newValues []T, key func(*T) string) FieldDiff {
+	diff := FieldDiff{Field: field}
+
+	oldByKey := make(map[string]*T, len(oldValues))
+	oldKeys := make([]string, 0, len(oldValues))
+	for index := range oldValues {
+		k := diffKey(&oldValues[index], key)
+		if _, ok := oldByKey[k]; !ok {
+			oldByKey[k] = &oldValues[index]
+			oldKeys = append(oldKeys, k)
+		}
+	}
+
+	seen := make(map[string]bool, len(newValues))
+	for index := range newValues {
+		value := &newValues[index]
+		k := diffKey(value, key)
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+
+		oldValue, ok := oldByKey[k]
+		if !ok {
+			diff.Added = append(diff.Added, DiffEntry{Key: k, Value: *value})
+			continue
+		}
+		if changed := changedAttributes(reflect.ValueOf(*oldValue), reflect.ValueOf(*value)); len(changed) > 0 {
+			diff.Modified = append(diff.Modified, DiffModification{Changed: changed, Key: k, New: *value, Old: *oldValue})
+		}
+	}
+
+	for _, k := range oldKeys {
+		if !seen[k] {
+			diff.Removed = append(diff.Removed, DiffEntry{Key: k, Value: *oldByKey[k]})
+		}
+	}
+	return diff
+}
+
+// diffKey returns the normalized key of the value (or its JSON encoding if there is no key)
+func diffKey[T any](value *T, key func(*T) string) string {
+	if k := key(value); len(k) > 0 {
+		return k
+	}
+	data, _ := json.Marshal(value)
+	return string(data)
+}
+
+// changedAttributes returns the JSON names of the attributes that are different (text is
+// compared ignoring case and surrounding whitespace)
+func changedAttributes(oldValue, newValue reflect.Value) []string {
+	var changed []string
+	for index := 0; index < oldValue.NumField(); index++ {
+		field := oldValue.Type().Field(index)
+		if !field.IsExported() {
+			continue
+		}
+
+		a, b := oldValue.Field(index), newValue.Field(index)
+		if a.Kind() == reflect.String {
+			if strings.EqualFold(strings.TrimSpace(a.String()), strings.TrimSpace(b.String())) {
+				continue
+			}
+		} else if reflect.DeepEqual(a.Interface(), b.Interface()) {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if len(name) == 0 || name == "-" {
+			name = field.Name
+		}
+		changed = append(changed, name)
+	}
+	return changed
+}
+
+// singleValue returns the single value field as a slice (empty if not set)
+func singleValue[T any](value *T) []T {
+	if value == nil {
+		return nil
+	}
+	return []T{*value}
+}
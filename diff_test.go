@@ -0,0 +1,132 @@
+package pipl
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDiffPersons will test comparing two snapshots of a person
+func TestDiffPersons(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no changes (normalized)", func(t *testing.T) {
+		oldPerson := &Person{
+			ID:     "abc",
+			Emails: []Email{{Address: "clark@example.com"}},
+			Phones: []Phone{{CountryCode: 1, Number: 9785550145}},
+		}
+		newPerson := &Person{
+			ID:     "abc",
+			Emails: []Email{{Address: " Clark@Example.com"}},
+			Phones: []Phone{{CountryCode: 1, Number: 9785550145}},
+		}
+		diff := DiffPersons(oldPerson, newPerson)
+		assert.False(t, diff.HasChanges())
+		assert.Equal(t, "person abc: no changes", diff.String())
+	})
+
+	t.Run("nil snapshots", func(t *testing.T) {
+		diff := DiffPersons(nil, &Person{Emails: []Email{{Address: "clark@example.com"}}})
+		require.Len(t, diff.Fields, 1)
+		assert.Len(t, diff.Fields[0].Added, 1)
+
+		diff = DiffPersons(&Person{Gender: &Gender{Content: "male"}}, nil)
+		require.Len(t, diff.Fields, 1)
+		assert.Equal(t, "gender", diff.Fields[0].Field)
+		assert.Len(t, diff.Fields[0].Removed, 1)
+
+		assert.False(t, DiffPersons(nil, nil).HasChanges())
+	})
+
+	t.Run("added, removed and modified", func(t *testing.T) {
+		oldPerson := &Person{
+			ID: "abc",
+			Emails: []Email{
+				{Address: "kent@example.com"},
+				{Address: "superman@example.com", LastSeen: "2018"},
+			},
+			Jobs: []Job{{Title: "Reporter", Organization: "Daily Planet"}},
+		}
+		newPerson := &Person{
+			ID: "abc",
+			Emails: []Email{
+				{Address: "superman@example.com", LastSeen: "2019", Current: true},
+				{Address: "clark@example.com"},
+			},
+			Jobs: []Job{{Title: "reporter", Organization: "Daily Planet", Industry: "News"}},
+			Relationships: []Relationship{
+				{Type: RelationshipTypeFamily, Names: []Name{{First: "Lois", Last: "Lane"}}},
+			},
+		}
+
+		diff := DiffPersons(oldPerson, newPerson)
+		require.True(t, diff.HasChanges())
+		assert.Equal(t, GUID("abc"), diff.ID)
+		require.Len(t, diff.Fields, 3)
+
+		emails := diff.Fields[0]
+		assert.Equal(t, "emails", emails.Field)
+		require.Len(t, emails.Added, 1)
+		assert.Equal(t, "clark@example.com", emails.Added[0].Key)
+		require.Len(t, emails.Removed, 1)
+		assert.Equal(t, "kent@example.com", emails.Removed[0].Key)
+		require.Len(t, emails.Modified, 1)
+		assert.Equal(t, []string{"@current", "@last_seen"}, emails.Modified[0].Changed)
+
+		jobs := diff.Fields[1]
+		assert.Equal(t, "jobs", jobs.Field)
+		assert.Empty(t, jobs.Modified)
+		require.Len(t, jobs.Added, 1) // The industry is part of the job key
+		require.Len(t, jobs.Removed, 1)
+
+		assert.Equal(t, "relationships", diff.Fields[2].Field)
+		assert.Equal(t, "family:lois lane", diff.Fields[2].Added[0].Key)
+
+		assert.Equal(t, `person abc: 3 added, 2 removed, 1 modified
+emails:
+  + clark@example.com
+  - kent@example.com
+  ~ superman@example.com (@current, @last_seen)
+jobs:
+  + reporter|daily planet|news
+  - reporter|daily planet|
+relationships:
+  + family:lois lane`, diff.String())
+	})
+
+	t.Run("json", func(t *testing.T) {
+		diff := DiffPersons(
+			&Person{ID: "abc", Usernames: []Username{{Content: "superman"}}},
+			&Person{ID: "abc", Usernames: []Username{{Content: "Superman", Current: true}}},
+		)
+		data, err := json.Marshal(diff)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{
+			"id": "abc",
+			"fields": [{
+				"field": "usernames",
+				"modified": [{
+					"key": "superman",
+					"changed": ["@current"],
+					"old": {"content": "superman"},
+					"new": {"content": "Superman", "@current": true}
+				}]
+			}]
+		}`, string(data))
+	})
+}
+
+// ExampleDiffPersons example using DiffPersons()
+func ExampleDiffPersons() {
+	lastMonth := &Person{ID: "abc", Emails: []Email{{Address: "clark@example.com"}}}
+	today := &Person{ID: "abc", Emails: []Email{{Address: "clark@example.com"}, {Address: "superman@example.com"}}}
+
+	fmt.Println(DiffPersons(lastMonth, today))
+	// Output:person abc: 1 added, 0 removed, 0 modified
+	// emails:
+	//   + superman@example.com
+}
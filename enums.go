@@ -0,0 +1,313 @@
+package pipl
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+)
+
+// AddressType is the @type of an address (Address.Type)
+//
+// Source: https://docs.pipl.com/reference#address
+type AddressType string
+
+const (
+	// AddressTypeHome is a home address
+	AddressTypeHome AddressType = "home"
+
+	// AddressTypeWork is a work address
+	AddressTypeWork AddressType = "work"
+
+	// AddressTypeOld is a previous (old) address
+	AddressTypeOld AddressType = "old"
+)
+
+// PhoneType is the @type of a phone (Phone.Type)
+//
+// Source: https://docs.pipl.com/reference#phone
+type PhoneType string
+
+const (
+	// PhoneTypeMobile is a mobile phone
+	PhoneTypeMobile PhoneType = "mobile"
+
+	// PhoneTypeHomePhone is a home (landline) phone
+	PhoneTypeHomePhone PhoneType = "home_phone"
+
+	// PhoneTypeHomeFax is a home fax
+	PhoneTypeHomeFax PhoneType = "home_fax"
+
+	// PhoneTypeWorkPhone is a work (landline) phone
+	PhoneTypeWorkPhone PhoneType = "work_phone"
+
+	// PhoneTypeWorkFax is a work fax
+	PhoneTypeWorkFax PhoneType = "work_fax"
+
+	// PhoneTypePager is a pager
+	PhoneTypePager PhoneType = "pager"
+)
+
+// EmailType is the @type of an email (Email.Type)
+//
+// Source: https://docs.pipl.com/reference#email
+type EmailType string
+
+const (
+	// EmailTypePersonal is a personal email
+	EmailTypePersonal EmailType = "personal"
+
+	// EmailTypeWork is a work email
+	EmailTypeWork EmailType = "work"
+)
+
+// RelationshipType is the @type of a relationship (Relationship.Type), the @subtype is free text (for example "Father")
+//
+// Source: https://docs.pipl.com/reference#relationship
+type RelationshipType string
+
+const (
+	// RelationshipTypeFamily is a family member
+	RelationshipTypeFamily RelationshipType = "family"
+
+	// RelationshipTypeWork is a work relationship (colleague, boss, etc.)
+	RelationshipTypeWork RelationshipType = "work"
+
+	// RelationshipTypeFriend is a friend (default)
+	RelationshipTypeFriend RelationshipType = "friend"
+
+	// RelationshipTypeOther is any other relationship
+	RelationshipTypeOther RelationshipType = "other"
+)
+
+// SourceCategory is the @category of a source or URL (Source.Category and URL.Category)
+//
+// Source: https://docs.pipl.com/reference#source
+type SourceCategory string
+
+const (
+	// SourceCategoryBackgroundReports is for background reports
+	SourceCategoryBackgroundReports SourceCategory = "background_reports"
+
+	// SourceCategoryContactDetails is for contact details
+	SourceCategoryContactDetails SourceCategory = "contact_details"
+
+	// SourceCategoryEmailAddress is for email addresses
+	SourceCategoryEmailAddress SourceCategory = "email_address"
+
+	// SourceCategoryMedia is for media (news, videos, etc.)
+	SourceCategoryMedia SourceCategory = "media"
+
+	// SourceCategoryPersonalProfiles is for personal (social) profiles
+	SourceCategoryPersonalProfiles SourceCategory = "personal_profiles"
+
+	// SourceCategoryProfessionalAndBusiness is for professional and business sources
+	SourceCategoryProfessionalAndBusiness SourceCategory = "professional_and_business"
+
+	// SourceCategoryPublicRecords is for public records
+	SourceCategoryPublicRecords SourceCategory = "public_records"
+
+	// SourceCategoryPublications is for publications
+	SourceCategoryPublications SourceCategory = "publications"
+
+	// SourceCategorySchoolAndClassmates is for school and classmates
+	SourceCategorySchoolAndClassmates SourceCategory = "school_and_classmates"
+
+	// SourceCategoryWebPages is for web pages
+	SourceCategoryWebPages SourceCategory = "web_pages"
+)
+
+// TagClassification is the @classification of a tag (Tag.Classification), see KnownTagClassifications
+//
+// Source: https://docs.pipl.com/reference#tag
+type TagClassification string
+
+// KnownTagClassifications are the valid tag classifications. Pipl does not document a fixed
+// list, so add the classifications you expect; tag classifications are only validated
+// (and flagged as unknown values) when the list is not empty.
+var KnownTagClassifications []TagClassification //nolint:gochecknoglobals // Public API for validation
+
+// UnknownValue is a typed value (from the API) that is not one of the documented values
+type UnknownValue struct {
+	Path  string `json:"path"`  // Path to the field, for example "person.phones[1].@type"
+	Value string `json:"value"` // The (kept) unknown value
+}
+
+// IsValid returns true if the value is a documented address type
+func (t AddressType) IsValid() bool {
+	switch t {
+	case AddressTypeHome, AddressTypeWork, AddressTypeOld:
+		return true
+	}
+	return false
+}
+
+// IsValid returns true if the value is a documented phone type
+func (t PhoneType) IsValid() bool {
+	switch t {
+	case PhoneTypeMobile, PhoneTypeHomePhone, PhoneTypeHomeFax, PhoneTypeWorkPhone, PhoneTypeWorkFax, PhoneTypePager:
+		return true
+	}
+	return false
+}
+
+// IsValid returns true if the value is a documented email type
+func (t EmailType) IsValid() bool {
+	switch t {
+	case EmailTypePersonal, EmailTypeWork:
+		return true
+	}
+	return false
+}
+
+// IsValid returns true if the value is a documented relationship type
+func (t RelationshipType) IsValid() bool {
+	switch t {
+	case RelationshipTypeFamily, RelationshipTypeWork, RelationshipTypeFriend, RelationshipTypeOther:
+		return true
+	}
+	return false
+}
+
+// IsValid returns true if the value is a documented source category
+func (c SourceCategory) IsValid() bool {
+	switch c {
+	case SourceCategoryBackgroundReports, SourceCategoryContactDetails, SourceCategoryEmailAddress,
+		SourceCategoryMedia, SourceCategoryPersonalProfiles, SourceCategoryProfessionalAndBusiness,
+		SourceCategoryPublicRecords, SourceCategoryPublications, SourceCategorySchoolAndClassmates,
+		SourceCategoryWebPages:
+		return true
+	}
+	return false
+}
+
+// IsValid returns true if the value is one of the KnownTagClassifications
+func (c TagClassification) IsValid() bool {
+	return slices.Contains(KnownTagClassifications, c)
+}
+
+// UnknownValues returns every typed value in the response that is set, but is not one of the
+// documented values. Unknown values are always kept when decoding, and DecodeModeStrict reports
+// them (for example, to notice new values from the API, or typos in a query).
+func (r *Response) UnknownValues() []UnknownValue {
+	var unknown []UnknownValue
+	unknown = r.Person.appendUnknownValues(unknown, "person")
+	for index := range r.PossiblePersons {
+		unknown = r.PossiblePersons[index].appendUnknownValues(unknown, "possible_persons["+strconv.Itoa(index)+"]")
+	}
+	for index := range r.Sources {
+		unknown = r.Sources[index].appendUnknownValues(unknown, "sources["+strconv.Itoa(index)+"]")
+	}
+	return unknown
+}
+
+// UnknownValues returns every typed value of the person that is set, but is not one of the documented values
+func (p *Person) UnknownValues() []UnknownValue {
+	return p.appendUnknownValues(nil, "person")
+}
+
+// UnknownValues returns every typed value of the source that is set, but is not one of the documented values
+func (s *Source) UnknownValues() []UnknownValue {
+	return s.appendUnknownValues(nil, "source")
+}
+
+// appendUnknownValues appends the unknown values of the person
+func (p *Person) appendUnknownValues(unknown []UnknownValue, path string) []UnknownValue {
+	unknown = appendUnknownFields(unknown, path, p.Addresses, p.Emails, p.Phones, p.Relationships)
+	for index, u := range p.URLs {
+		unknown = appendUnknown(unknown, fmt.Sprintf("%s.urls[%d].@category", path, index), u.Category)
+	}
+	return unknown
+}
+
+// appendUnknownValues appends the unknown values of the source
+func (s *Source) appendUnknownValues(unknown []UnknownValue, path string) []UnknownValue {
+	unknown = appendUnknown(unknown, path+".@category", s.Category)
+	unknown = appendUnknownFields(unknown, path, s.Addresses, s.Emails, s.Phones, s.Relationships)
+	for index, u := range s.URLs {
+		unknown = appendUnknown(unknown, fmt.Sprintf("%s.urls[%d].@category", path, index), u.Category)
+	}
+	for index, tag := range s.Tags {
+		if len(KnownTagClassifications) == 0 {
+			break
+		}
+		unknown = appendUnknown(unknown, fmt.Sprintf("%s.tags[%d].@classification", path, index), tag.Classification)
+	}
+	return unknown
+}
+
+// appendUnknownFields appends the unknown values of the common (typed) field slices
+func appendUnknownFields(unknown []UnknownValue, path string, addresses []Address, emails []Email,
+	phones []Phone, relationships []Relationship,
+) []UnknownValue {
+	for index, address := range addresses {
+		unknown = appendUnknown(unknown, fmt.Sprintf("%s.addresses[%d].@type", path, index), address.Type)
+	}
+	for index, email := range emails {
+		unknown = appendUnknown(unknown, fmt.Sprintf("%s.emails[%d].@type", path, index), email.Type)
+	}
+	for index, phone := range phones {
+		unknown = appendUnknown(unknown, fmt.Sprintf("%s.phones[%d].@type", path, index), phone.Type)
+	}
+	for index, relationship := range relationships {
+		relationshipPath := fmt.Sprintf("%s.relationships[%d]", path, index)
+		unknown = appendUnknown(unknown, relationshipPath+".@type", relationship.Type)
+		unknown = appendUnknownFields(unknown, relationshipPath, relationship.Addresses, relationship.Emails,
+			relationship.Phones, relationship.Relationships)
+	}
+	return unknown
+}
+
+// enum is a typed value with documented values
+type enum interface {
+	~string
+	IsValid() bool
+}
+
+// appendUnknown appends the value if it is set, but not valid
+func appendUnknown[T enum](unknown []UnknownValue, path string, value T) []UnknownValue {
+	if len(value) == 0 || value.IsValid() {
+		return unknown
+	}
+	return append(unknown, UnknownValue{Path: path, Value: string(value)})
+}
+
+// UnmarshalJSON will decode the address type, keeping an unknown value (IsValid() is false)
+func (t *AddressType) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, t)
+}
+
+// UnmarshalJSON will decode the phone type, keeping an unknown value (IsValid() is false)
+func (t *PhoneType) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, t)
+}
+
+// UnmarshalJSON will decode the email type, keeping an unknown value (IsValid() is false)
+func (t *EmailType) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, t)
+}
+
+// UnmarshalJSON will decode the relationship type, keeping an unknown value (IsValid() is false)
+func (t *RelationshipType) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, t)
+}
+
+// UnmarshalJSON will decode the source category, keeping an unknown value (IsValid() is false)
+func (c *SourceCategory) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, c)
+}
+
+// UnmarshalJSON will decode the tag classification, keeping an unknown value (IsValid() is false)
+func (c *TagClassification) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, c)
+}
+
+// unmarshalEnum decodes a JSON string into the typed value, unknown values are kept as is
+func unmarshalEnum[T enum](data []byte, value *T) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*value = T(raw)
+	return nil
+}
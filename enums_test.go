@@ -0,0 +1,116 @@
+package pipl
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestEnums_IsValid will test the validation of the typed values
+func TestEnums_IsValid(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid values", func(t *testing.T) {
+		assert.True(t, AddressTypeOld.IsValid())
+		assert.True(t, PhoneTypeWorkFax.IsValid())
+		assert.True(t, EmailTypePersonal.IsValid())
+		assert.True(t, RelationshipTypeFamily.IsValid())
+		assert.True(t, SourceCategoryBackgroundReports.IsValid())
+	})
+
+	t.Run("typed fields accept the constants and string literals", func(t *testing.T) {
+		phone := Phone{Type: PhoneTypeMobile}
+		assert.True(t, phone.Type.IsValid())
+		phone.Type = "satellite"
+		assert.False(t, phone.Type.IsValid())
+	})
+
+	t.Run("tag classifications are not documented", func(t *testing.T) {
+		assert.False(t, TagClassification("content").IsValid())
+	})
+
+	t.Run("invalid values", func(t *testing.T) {
+		assert.False(t, AddressType("hom").IsValid())
+		assert.False(t, PhoneType("cell").IsValid())
+		assert.False(t, EmailType("").IsValid())
+		assert.False(t, RelationshipType("Family").IsValid())
+		assert.False(t, SourceCategory("professional").IsValid())
+	})
+}
+
+// TestEnums_UnmarshalJSON will test decoding the typed values
+func TestEnums_UnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	t.Run("known and unknown values are kept", func(t *testing.T) {
+		var address Address
+		require.NoError(t, json.Unmarshal([]byte(`{"@type": "home"}`), &address))
+		assert.Equal(t, AddressTypeHome, address.Type)
+
+		var source Source
+		require.NoError(t, json.Unmarshal([]byte(`{"@category": "dark_web", "tags": [{"@classification": "social"}]}`), &source))
+		assert.Equal(t, SourceCategory("dark_web"), source.Category)
+		assert.False(t, source.Category.IsValid())
+		assert.Equal(t, TagClassification("social"), source.Tags[0].Classification)
+	})
+
+	t.Run("null is empty", func(t *testing.T) {
+		var phone Phone
+		require.NoError(t, json.Unmarshal([]byte(`{"@type": null}`), &phone))
+		assert.Empty(t, phone.Type)
+	})
+
+	t.Run("non-string values are rejected", func(t *testing.T) {
+		var email Email
+		require.Error(t, json.Unmarshal([]byte(`{"@type": 5}`), &email))
+		var relationship Relationship
+		require.Error(t, json.Unmarshal([]byte(`{"@type": ["family"]}`), &relationship))
+	})
+}
+
+// TestResponse_UnknownValues will test flagging unknown values (and keeping them)
+func TestResponse_UnknownValues(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no unknown values", func(t *testing.T) {
+		response, err := loadResponseData("response_success.json")
+		require.NoError(t, err)
+		assert.Empty(t, response.UnknownValues())
+	})
+
+	t.Run("unknown values are kept and flagged", func(t *testing.T) {
+		data := []byte(`{
+			"person": {
+				"phones": [{"@type": "mobile"}, {"@type": "satellite"}],
+				"relationships": [{"@type": "rival", "emails": [{"@type": "school"}]}],
+				"urls": [{"@category": "web_pages"}]
+			},
+			"sources": [{"@category": "dark_web", "tags": [{"@classification": "", "content": "x"}]}]
+		}`)
+		response := new(Response)
+		require.NoError(t, json.Unmarshal(data, response))
+
+		assert.Equal(t, PhoneType("satellite"), response.Person.Phones[1].Type)
+		assert.Equal(t, []UnknownValue{
+			{Path: "person.phones[1].@type", Value: "satellite"},
+			{Path: "person.relationships[0].@type", Value: "rival"},
+			{Path: "person.relationships[0].emails[0].@type", Value: "school"},
+			{Path: "sources[0].@category", Value: "dark_web"},
+		}, response.UnknownValues())
+		assert.Len(t, response.Person.UnknownValues(), 3)
+	})
+}
+
+// TestKnownTagClassifications will test validating tag classifications (not parallel, it sets the registry)
+func TestKnownTagClassifications(t *testing.T) {
+	KnownTagClassifications = []TagClassification{"content", "social"}
+	t.Cleanup(func() { KnownTagClassifications = nil })
+
+	assert.True(t, TagClassification("social").IsValid())
+	assert.False(t, TagClassification("").IsValid())
+
+	source := &Source{Tags: []Tag{{Classification: "content"}, {Classification: "sports"}}}
+	assert.Equal(t, []UnknownValue{{Path: "source.tags[1].@classification", Value: "sports"}}, source.UnknownValues())
+}
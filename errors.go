@@ -1,6 +1,13 @@
 package pipl
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
 
 // ErrDoesNotMeetMinimumCriteria is when the minimum search criteria is not met
 var ErrDoesNotMeetMinimumCriteria = errors.New("the search request submitted does not contain enough sufficient terms. " +
@@ -33,6 +40,9 @@ var ErrUserIDTooShort = errors.New("user_id is too short")
 // ErrURLTooShort is when the URL is too short
 var ErrURLTooShort = errors.New("url is too short")
 
+// ErrInvalidImageURL is when the image URL is not an http(s) URL
+var ErrInvalidImageURL = errors.New("image url is not an http(s) url")
+
 // ErrServiceProviderTooShort is when the SERVICE_PROVIDER is too short
 var ErrServiceProviderTooShort = errors.New("service_provider is too short")
 
@@ -48,6 +58,12 @@ var ErrInvalidPhoneNumber = errors.New("invalid phone number")
 // ErrMissingCountryCode is when the COUNTRY_CODE is missing from the phone number
 var ErrMissingCountryCode = errors.New("missing country code")
 
+// ErrUnknownCountryCode is when the COUNTRY_CODE is not a known country calling code
+var ErrUnknownCountryCode = errors.New("unknown country code")
+
+// ErrInvalidPhoneLength is when the PHONE length is not valid for the country calling code
+var ErrInvalidPhoneLength = errors.New("invalid phone number length for the country code")
+
 // ErrMissingNumberOrStreet is when the NUMBER or STREET is missing from an address
 var ErrMissingNumberOrStreet = errors.New("missing number or street")
 
@@ -81,6 +97,9 @@ var ErrInvalidCountryCode = errors.New("invalid country code")
 // ErrInvalidGender is when the GENDER is invalid
 var ErrInvalidGender = errors.New("invalid gender")
 
+// ErrInvalidRelationshipType is when the relationship TYPE is not one of the documented types
+var ErrInvalidRelationshipType = errors.New("invalid relationship type")
+
 // ErrMissingRequest is when the HTTP request is missing
 var ErrMissingRequest = errors.New("missing request")
 
@@ -92,3 +111,95 @@ var ErrBadRequest = errors.New("bad request")
 
 // ErrAPIResponse is when the API returns an error response
 var ErrAPIResponse = errors.New("API response error")
+
+// ErrMissingPerson is when the PERSON to search is missing (nil)
+var ErrMissingPerson = errors.New("missing person")
+
+// ErrBatchAborted is when a batch item was not searched because the batch was stopped
+var ErrBatchAborted = errors.New("batch aborted before the item was searched")
+
+// ErrInvalidJobID is when the JOB_ID is empty or not usable as a directory name
+var ErrInvalidJobID = errors.New("invalid job id")
+
+// ErrInvalidDate is when a date is not one of the Pipl formats (YYYY, YYYY-MM, YYYY-MM-DD)
+var ErrInvalidDate = errors.New("invalid date")
+
+// ErrUnknownPersonField is when a field name is not a (flattenable) person field, for example "emails"
+var ErrUnknownPersonField = errors.New("unknown person field")
+
+// ErrInvalidVCard is when the data does not contain a vCard (BEGIN:VCARD ... END:VCARD)
+var ErrInvalidVCard = errors.New("invalid vcard")
+
+// ErrUnknownFields is when the JSON has fields that are not in the structs (DecodeModeStrict)
+var ErrUnknownFields = errors.New("unknown fields")
+
+// PartialResultError is when some (but not all) of the possible persons could not be expanded.
+// The response is still returned, and the failed possible persons are left as previews.
+type PartialResultError struct {
+	// Errors are the search pointer errors, by index of Response.PossiblePersons
+	Errors map[int]error
+}
+
+// Error returns a summary of all the failed search pointers
+func (e *PartialResultError) Error() string {
+	indexes := make([]int, 0, len(e.Errors))
+	for index := range e.Errors {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+
+	messages := make([]string, 0, len(indexes))
+	for _, index := range indexes {
+		messages = append(messages, fmt.Sprintf("possible person %d: %s", index, e.Errors[index]))
+	}
+	return fmt.Sprintf("%d possible person(s) failed: %s", len(indexes), strings.Join(messages, "; "))
+}
+
+// Unwrap returns all the search pointer errors (for use with errors.Is and errors.As)
+func (e *PartialResultError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// FieldError is a validation error of a search person field, with the field path
+// (for example, "emails[1]" or "dob") (see PersonBuilder)
+type FieldError struct {
+	Err  error
+	Path string
+}
+
+// Error returns the field path and the validation error
+func (e *FieldError) Error() string {
+	return e.Path + ": " + e.Err.Error()
+}
+
+// Unwrap returns the validation error (for errors.Is, for example ErrInvalidEmailAddress)
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// UnknownFieldsError is returned by DecodeModeStrict with the paths of every unknown field
+// (for example, "person.names[0].nickname"), and every undocumented typed value (see UnknownValues)
+//
+// DO NOT CHANGE ORDER - Optimized for memory (malign)
+type UnknownFieldsError struct {
+	Fields []string
+	Values []UnknownValue
+}
+
+// Error returns the unknown field paths, and the unknown values (path="value")
+func (e *UnknownFieldsError) Error() string {
+	unknown := slices.Clone(e.Fields)
+	for _, value := range e.Values {
+		unknown = append(unknown, value.Path+"="+strconv.Quote(value.Value))
+	}
+	return ErrUnknownFields.Error() + ": " + strings.Join(unknown, ", ")
+}
+
+// Unwrap returns ErrUnknownFields (for errors.Is)
+func (e *UnknownFieldsError) Unwrap() error {
+	return ErrUnknownFields
+}
@@ -0,0 +1,343 @@
+package pipl
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// TableManifestVersion is the version of the table manifest format (see Flattener.Manifest)
+const TableManifestVersion = 1
+
+// Column is a single column of a flattened person
+//
+// DO NOT CHANGE ORDER - Optimized for memory (malign)
+type Column struct {
+	Value       func(p *Person) string // The value of the column for the person (of the row)
+	Description string                 // Description for the manifest, for example "emails[0]"
+	Name        string                 // Header of the column, for example "email_1"
+}
+
+// Flattener will flatten persons (and responses) into rows, for spreadsheets and CSV exports.
+//
+// The columns only depend on the Flattener (never on the data), so exports from different
+// runs with the same columns line up (see Manifest).
+//
+// DO NOT CHANGE ORDER - Optimized for memory (malign)
+type Flattener struct {
+	Columns       []Column // The columns of every row (see DefaultColumns and FieldColumns)
+	RowPer        string   // One row per value of this field (for example "phones"), or one row per person if empty
+	AllowFormulas bool     // Write the CSV cells as they are (by default, cells that could be a spreadsheet formula are escaped)
+}
+
+// TableManifest describes the columns of an export, to line up exports from different runs
+//
+// DO NOT CHANGE ORDER - Optimized for memory (malign)
+type TableManifest struct {
+	Columns     []ManifestColumn `json:"columns"`
+	Fingerprint string           `json:"fingerprint"` // SHA-256 of the column names
+	RowPer      string           `json:"row_per,omitempty"`
+	Version     int              `json:"version"`
+}
+
+// ManifestColumn is a single column of the manifest
+type ManifestColumn struct {
+	Description string `json:"description,omitempty"`
+	Name        string `json:"name"`
+}
+
+// flatField is a person field that can be flattened
+type flatField struct {
+	count  func(p *Person) int               // Number of values
+	only   func(p *Person, index int)        // Keep only the value at the index (one row per value)
+	value  func(p *Person, index int) string // Display value at the index, or empty
+	column string                            // Column prefix, for example "email"
+	single bool                              // Single value (dob, gender)
+}
+
+// flatFields are the person fields that can be flattened, by JSON name
+var flatFields = map[string]flatField{ //nolint:gochecknoglobals // Lookup table
+	"addresses": sliceField("address", func(p *Person) *[]Address { return &p.Addresses }, func(v *Address) string {
+		return cmp.Or(v.Display, v.Raw)
+	}),
+	"dob": pointerField("dob", func(p *Person) **DateOfBirth { return &p.DateOfBirth }, func(v *DateOfBirth) string {
+		return cmp.Or(v.Display, v.DateRange.Start)
+	}),
+	"educations": sliceField("education", func(p *Person) *[]Education { return &p.Educations }, func(v *Education) string {
+		return cmp.Or(v.Display, v.School)
+	}),
+	"emails": sliceField("email", func(p *Person) *[]Email { return &p.Emails }, func(v *Email) string {
+		return v.Address
+	}),
+	"ethnicities": sliceField("ethnicity", func(p *Person) *[]Ethnicity { return &p.Ethnicities }, func(v *Ethnicity) string {
+		return v.Content
+	}),
+	"gender": pointerField("gender", func(p *Person) **Gender { return &p.Gender }, func(v *Gender) string {
+		return v.Content
+	}),
+	"images": sliceField("image", func(p *Person) *[]Image { return &p.Images }, func(v *Image) string {
+		return v.URL
+	}),
+	"jobs": sliceField("job", func(p *Person) *[]Job { return &p.Jobs }, func(v *Job) string {
+		return cmp.Or(v.Display, v.Title)
+	}),
+	"languages": sliceField("language", func(p *Person) *[]Language { return &p.Languages }, func(v *Language) string {
+		return cmp.Or(v.Display, v.Language)
+	}),
+	"names": sliceField("name", func(p *Person) *[]Name { return &p.Names }, func(v *Name) string {
+		return cmp.Or(v.Display, v.Raw)
+	}),
+	"origin_countries": sliceField("origin_country", func(p *Person) *[]OriginCountry { return &p.OriginCountries }, func(v *OriginCountry) string {
+		return v.Country
+	}),
+	"phones": sliceField("phone", func(p *Person) *[]Phone { return &p.Phones }, func(v *Phone) string {
+		return cmp.Or(v.DisplayInternational, v.Display, v.Raw)
+	}),
+	"relationships": sliceField("relationship", func(p *Person) *[]Relationship { return &p.Relationships }, func(v *Relationship) string {
+		if len(v.Names) == 0 {
+			return ""
+		}
+		return cmp.Or(v.Names[0].Display, v.Names[0].Raw)
+	}),
+	"urls": sliceField("url", func(p *Person) *[]URL { return &p.URLs }, func(v *URL) string {
+		return v.URL
+	}),
+	"user_ids": sliceField("user_id", func(p *Person) *[]UserID { return &p.UserIDs }, func(v *UserID) string {
+		return v.Content
+	}),
+	"usernames": sliceField("username", func(p *Person) *[]Username { return &p.Usernames }, func(v *Username) string {
+		return v.Content
+	}),
+	"vehicles": sliceField("vehicle", func(p *Person) *[]Vehicle { return &p.Vehicles }, func(v *Vehicle) string {
+		return cmp.Or(v.Display, v.VIN)
+	}),
+}
+
+// DefaultColumns returns the default columns: the person @id, @search_pointer and @match, then the
+// first values of the main fields (for example, email_1 to email_3)
+func DefaultColumns() []Column {
+	columns := []Column{
+		{Name: "person_id", Description: "@id", Value: func(p *Person) string { return string(p.ID) }},
+		{Name: "search_pointer", Description: "@search_pointer", Value: func(p *Person) string { return p.SearchPointer }},
+		{Name: "match", Description: "@match", Value: func(p *Person) string {
+			return strconv.FormatFloat(float64(p.Match), 'f', -1, 32)
+		}},
+	}
+	for _, field := range []struct {
+		name  string
+		count int
+	}{
+		{"names", 1}, {"dob", 1}, {"gender", 1}, {"emails", 3}, {"phones", 3}, {"addresses", 2},
+		{"jobs", 1}, {"educations", 1}, {"urls", 3}, {"usernames", 2}, {"user_ids", 2}, {"images", 1},
+	} {
+		columns = append(columns, fieldColumns(field.name, flatFields[field.name], field.count)...)
+	}
+	return columns
+}
+
+// FieldColumns returns the columns of the first values of the person field (by JSON name, for example "emails"),
+// named "email_1" to "email_<count>". Single value fields ("dob" and "gender") have a single column.
+func FieldColumns(field string, count int) ([]Column, error) {
+	flat, ok := flatFields[field]
+	if !ok {
+		return nil, ErrUnknownPersonField
+	}
+	return fieldColumns(field, flat, count), nil
+}
+
+// fieldColumns returns the columns of the field
+func fieldColumns(field string, flat flatField, count int) []Column {
+	if flat.single {
+		return []Column{{Name: flat.column, Description: field, Value: func(p *Person) string { return flat.value(p, 0) }}}
+	}
+
+	columns := make([]Column, 0, count)
+	for index := 0; index < count; index++ {
+		columns = append(columns, Column{
+			Name:        flat.column + "_" + strconv.Itoa(index+1),
+			Description: field + "[" + strconv.Itoa(index) + "]",
+			Value:       func(p *Person) string { return flat.value(p, index) },
+		})
+	}
+	return columns
+}
+
+// Header returns the column names
+func (f *Flattener) Header() []string {
+	header := make([]string, 0, len(f.Columns))
+	for _, column := range f.Columns {
+		header = append(header, column.Name)
+	}
+	return header
+}
+
+// Rows will flatten the persons into rows (one per person, or one per value of RowPer)
+func (f *Flattener) Rows(persons ...*Person) ([][]string, error) {
+	var rowPer flatField
+	if len(f.RowPer) > 0 {
+		var ok bool
+		if rowPer, ok = flatFields[f.RowPer]; !ok {
+			return nil, ErrUnknownPersonField
+		}
+	}
+
+	rows := [][]string{}
+	for _, person := range persons {
+		if person == nil {
+			continue
+		}
+
+		// One row per value: each row has a copy of the person with only that value
+		if rowPer.only == nil || rowPer.count(person) <= 1 {
+			rows = append(rows, f.row(person))
+			continue
+		}
+		for index := 0; index < rowPer.count(person); index++ {
+			single := *person
+			rowPer.only(&single, index)
+			rows = append(rows, f.row(&single))
+		}
+	}
+	return rows, nil
+}
+
+// ResponseRows will flatten the person and the possible persons of the responses into rows
+func (f *Flattener) ResponseRows(responses ...*Response) ([][]string, error) {
+	return f.Rows(responsePersons(responses)...)
+}
+
+// WriteCSV will write the header and the rows of the persons as CSV
+//
+// Cells that start with "=", "+", "-", "@", a tab or a carriage return are prefixed with a
+// single quote, so spreadsheets do not run them as formulas (for example, "+1 978-555-0145"
+// is written as "'+1 978-555-0145"), unless AllowFormulas is set.
+func (f *Flattener) WriteCSV(w io.Writer, persons ...*Person) error {
+	rows, err := f.Rows(persons...)
+	if err != nil {
+		return err
+	}
+
+	header := f.Header()
+	if !f.AllowFormulas {
+		escapeFormulas(header)
+		for _, row := range rows {
+			escapeFormulas(row)
+		}
+	}
+
+	writer := csv.NewWriter(w)
+	if err = writer.Write(header); err != nil {
+		return err
+	}
+	return writer.WriteAll(rows)
+}
+
+// escapeFormulas prefixes (in place) the cells that a spreadsheet could run as a formula with a single quote
+func escapeFormulas(cells []string) {
+	for index, cell := range cells {
+		if len(cell) > 0 && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+			cells[index] = "'" + cell
+		}
+	}
+}
+
+// WriteResponseCSV will write the header and the rows of the responses as CSV (see ResponseRows)
+func (f *Flattener) WriteResponseCSV(w io.Writer, responses ...*Response) error {
+	return f.WriteCSV(w, responsePersons(responses)...)
+}
+
+// Manifest returns the manifest of the columns
+func (f *Flattener) Manifest() TableManifest {
+	manifest := TableManifest{
+		Columns: make([]ManifestColumn, 0, len(f.Columns)),
+		RowPer:  f.RowPer,
+		Version: TableManifestVersion,
+	}
+	for _, column := range f.Columns {
+		manifest.Columns = append(manifest.Columns, ManifestColumn{Description: column.Description, Name: column.Name})
+	}
+	hash := sha256.Sum256([]byte(strings.Join(f.Header(), "\n")))
+	manifest.Fingerprint = hex.EncodeToString(hash[:])
+	return manifest
+}
+
+// WriteManifest will write the manifest as JSON
+func (f *Flattener) WriteManifest(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(f.Manifest())
+}
+
+// row returns the values of the columns for the person
+func (f *Flattener) row(person *Person) []string {
+	row := make([]string, 0, len(f.Columns))
+	for _, column := range f.Columns {
+		if column.Value == nil {
+			row = append(row, "")
+			continue
+		}
+		row = append(row, column.Value(person))
+	}
+	return row
+}
+
+// responsePersons returns the person (if any) and the possible persons of the responses
+func responsePersons(responses []*Response) []*Person {
+	var persons []*Person
+	for _, response := range responses {
+		if response == nil {
+			continue
+		}
+		if !response.Person.isEmpty() {
+			persons = append(persons, &response.Person)
+		}
+		for index := range response.PossiblePersons {
+			persons = append(persons, &response.PossiblePersons[index])
+		}
+	}
+	return persons
+}
+
+// sliceField returns the flat field of a person slice field
+func sliceField[T any](column string, get func(*Person) *[]T, display func(*T) string) flatField {
+	return flatField{
+		column: column,
+		count:  func(p *Person) int { return len(*get(p)) },
+		only: func(p *Person, index int) {
+			values := *get(p)
+			*get(p) = values[index : index+1 : index+1]
+		},
+		value: func(p *Person, index int) string {
+			if values := *get(p); index < len(values) {
+				return display(&values[index])
+			}
+			return ""
+		},
+	}
+}
+
+// pointerField returns the flat field of a person single value (pointer) field
+func pointerField[T any](column string, get func(*Person) **T, display func(*T) string) flatField {
+	return flatField{
+		column: column,
+		count: func(p *Person) int {
+			if *get(p) == nil {
+				return 0
+			}
+			return 1
+		},
+		only: func(*Person, int) {},
+		value: func(p *Person, index int) string {
+			if value := *get(p); value != nil && index == 0 {
+				return display(value)
+			}
+			return ""
+		},
+		single: true,
+	}
+}
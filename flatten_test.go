@@ -0,0 +1,189 @@
+package pipl
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestFlattenPerson returns a person with several emails and phones
+func newTestFlattenPerson() *Person {
+	return &Person{
+		ID:    "p1",
+		Match: 0.9,
+		Names: []Name{{Display: "Clark Kent"}},
+		Emails: []Email{
+			{Address: "clark@example.com"},
+			{Address: "kent@example.com"},
+		},
+		Gender: &Gender{Content: "male"},
+		Phones: []Phone{
+			{DisplayInternational: "+1 978-555-0145"},
+			{Display: "(978) 555-0146"},
+			{Raw: "978.555.0147"},
+		},
+	}
+}
+
+// TestFieldColumns will test the columns of a person field
+func TestFieldColumns(t *testing.T) {
+	t.Parallel()
+
+	t.Run("first values", func(t *testing.T) {
+		columns, err := FieldColumns("emails", 3)
+		require.NoError(t, err)
+		flattener := &Flattener{Columns: columns}
+		assert.Equal(t, []string{"email_1", "email_2", "email_3"}, flattener.Header())
+		assert.Equal(t, "emails[1]", columns[1].Description)
+
+		person := newTestFlattenPerson()
+		assert.Equal(t, "kent@example.com", columns[1].Value(person))
+		assert.Empty(t, columns[2].Value(person))
+	})
+
+	t.Run("single value", func(t *testing.T) {
+		columns, err := FieldColumns("gender", 3)
+		require.NoError(t, err)
+		require.Len(t, columns, 1)
+		assert.Equal(t, "gender", columns[0].Name)
+		assert.Equal(t, "male", columns[0].Value(newTestFlattenPerson()))
+		assert.Empty(t, columns[0].Value(&Person{}))
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		_, err := FieldColumns("nicknames", 1)
+		require.ErrorIs(t, err, ErrUnknownPersonField)
+	})
+}
+
+// TestFlattener_Rows will test flattening persons into rows
+func TestFlattener_Rows(t *testing.T) {
+	t.Parallel()
+
+	phones, err := FieldColumns("phones", 2)
+	require.NoError(t, err)
+	columns := append([]Column{{Name: "person_id", Value: func(p *Person) string { return string(p.ID) }}}, phones...)
+
+	t.Run("one row per person", func(t *testing.T) {
+		flattener := &Flattener{Columns: columns}
+		rows, err := flattener.Rows(newTestFlattenPerson(), nil, &Person{ID: "p2"})
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{
+			{"p1", "+1 978-555-0145", "(978) 555-0146"},
+			{"p2", "", ""},
+		}, rows)
+	})
+
+	t.Run("one row per phone", func(t *testing.T) {
+		flattener := &Flattener{Columns: columns, RowPer: "phones"}
+		person := newTestFlattenPerson()
+		rows, err := flattener.Rows(person, &Person{ID: "p2"})
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{
+			{"p1", "+1 978-555-0145", ""},
+			{"p1", "(978) 555-0146", ""},
+			{"p1", "978.555.0147", ""},
+			{"p2", "", ""},
+		}, rows)
+		assert.Len(t, person.Phones, 3) // Not changed
+	})
+
+	t.Run("unknown row field", func(t *testing.T) {
+		flattener := &Flattener{Columns: columns, RowPer: "nicknames"}
+		_, err := flattener.Rows(newTestFlattenPerson())
+		require.ErrorIs(t, err, ErrUnknownPersonField)
+	})
+
+	t.Run("response rows", func(t *testing.T) {
+		response, err := loadResponseData("response_possible_persons.json")
+		require.NoError(t, err)
+
+		flattener := &Flattener{Columns: DefaultColumns()}
+		rows, err := flattener.ResponseRows(response)
+		require.NoError(t, err)
+		require.Len(t, rows, len(response.PossiblePersons))
+		for _, row := range rows {
+			assert.Len(t, row, len(flattener.Columns))
+		}
+	})
+}
+
+// TestFlattener_WriteCSV will test the CSV export and manifest
+func TestFlattener_WriteCSV(t *testing.T) {
+	t.Parallel()
+
+	t.Run("header and rows", func(t *testing.T) {
+		flattener := &Flattener{Columns: DefaultColumns()}
+		var buffer bytes.Buffer
+		require.NoError(t, flattener.WriteCSV(&buffer, newTestFlattenPerson()))
+
+		lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+		require.Len(t, lines, 2)
+		assert.True(t, strings.HasPrefix(lines[0], "person_id,search_pointer,match,name_1,dob,gender,email_1,email_2,email_3,phone_1"))
+		assert.True(t, strings.HasPrefix(lines[1], "p1,,0.9,Clark Kent,,male,clark@example.com,kent@example.com,,'+1 978-555-0145"))
+	})
+
+	t.Run("formulas are escaped", func(t *testing.T) {
+		columns, err := FieldColumns("names", 6)
+		require.NoError(t, err)
+		person := &Person{Names: []Name{
+			{Display: `=HYPERLINK("http://evil.example.com","x")`}, {Display: "+1+1"}, {Display: "-2+3"},
+			{Display: "@SUM(A1:A2)"}, {Display: "\t=1+1"}, {Display: "Clark = Superman"},
+		}}
+
+		var buffer bytes.Buffer
+		require.NoError(t, (&Flattener{Columns: columns}).WriteCSV(&buffer, person))
+		records, err := csv.NewReader(&buffer).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 2)
+		assert.Equal(t, []string{
+			`'=HYPERLINK("http://evil.example.com","x")`, "'+1+1", "'-2+3", "'@SUM(A1:A2)", "'\t=1+1", "Clark = Superman",
+		}, records[1])
+
+		// Formulas can be allowed
+		buffer.Reset()
+		require.NoError(t, (&Flattener{Columns: columns, AllowFormulas: true}).WriteCSV(&buffer, person))
+		records, err = csv.NewReader(&buffer).ReadAll()
+		require.NoError(t, err)
+		assert.Equal(t, "+1+1", records[1][1])
+	})
+
+	t.Run("header without rows", func(t *testing.T) {
+		flattener := &Flattener{Columns: DefaultColumns()}
+		var buffer bytes.Buffer
+		require.NoError(t, flattener.WriteResponseCSV(&buffer, &Response{}))
+		assert.Equal(t, strings.Join(flattener.Header(), ",")+"\n", buffer.String())
+	})
+
+	t.Run("manifest", func(t *testing.T) {
+		flattener := &Flattener{Columns: DefaultColumns(), RowPer: "phones"}
+		var buffer bytes.Buffer
+		require.NoError(t, flattener.WriteManifest(&buffer))
+
+		var manifest TableManifest
+		require.NoError(t, json.Unmarshal(buffer.Bytes(), &manifest))
+		assert.Equal(t, TableManifestVersion, manifest.Version)
+		assert.Equal(t, "phones", manifest.RowPer)
+		assert.Len(t, manifest.Columns, len(flattener.Columns))
+		assert.Equal(t, ManifestColumn{Description: "emails[0]", Name: "email_1"}, manifest.Columns[6])
+
+		// Stable between runs, different for other columns
+		assert.Equal(t, manifest.Fingerprint, (&Flattener{Columns: DefaultColumns()}).Manifest().Fingerprint)
+		assert.NotEqual(t, manifest.Fingerprint, (&Flattener{Columns: DefaultColumns()[1:]}).Manifest().Fingerprint)
+	})
+}
+
+// ExampleFlattener_WriteCSV example using WriteCSV()
+func ExampleFlattener_WriteCSV() {
+	emails, _ := FieldColumns("emails", 2)
+	flattener := &Flattener{Columns: emails}
+	_ = flattener.WriteCSV(os.Stdout, &Person{Emails: []Email{{Address: "clark@example.com"}}})
+	// Output:email_1,email_2
+	// clark@example.com,
+}
@@ -0,0 +1,451 @@
+package pipl
+
+import (
+	"cmp"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// GraphNodeKind is the kind of node in an identity graph
+type GraphNodeKind string
+
+const (
+	// GraphNodePerson is a person (person, possible person or related person)
+	GraphNodePerson GraphNodeKind = "person"
+
+	// GraphNodeEmail is an email address
+	GraphNodeEmail GraphNodeKind = "email"
+
+	// GraphNodePhone is a phone number
+	GraphNodePhone GraphNodeKind = "phone"
+
+	// GraphNodeAddress is a physical address
+	GraphNodeAddress GraphNodeKind = "address"
+
+	// GraphNodeUsername is a username
+	GraphNodeUsername GraphNodeKind = "username"
+)
+
+// GraphEdgeKind is the kind of edge in an identity graph
+type GraphEdgeKind string
+
+const (
+	// GraphEdgeIdentifier links a person to one of its identifiers (email, phone, address, username)
+	GraphEdgeIdentifier GraphEdgeKind = "identifier"
+
+	// GraphEdgeRelationship links a person to a related person (with the relationship @type/@subtype)
+	GraphEdgeRelationship GraphEdgeKind = "relationship"
+)
+
+// GraphOptions are the settings for building an identity graph
+type GraphOptions struct {
+	// IncludeInferred will include inferred (@inferred) data, otherwise it is excluded
+	IncludeInferred bool
+}
+
+// GraphNode is a person or identifier in the graph
+//
+// DO NOT CHANGE ORDER - Optimized for memory (malign)
+type GraphNode struct {
+	ID       string        `json:"id"`
+	Kind     GraphNodeKind `json:"kind"`
+	Label    string        `json:"label"`
+	Inferred bool          `json:"inferred,omitempty"`
+}
+
+// GraphEdge links two nodes (by ID). Persons that share an identifier are
+// linked through the (single) identifier node.
+//
+// DO NOT CHANGE ORDER - Optimized for memory (malign)
+type GraphEdge struct {
+	ID       string           `json:"id"`
+	Kind     GraphEdgeKind    `json:"kind"`
+	Source   string           `json:"source"`
+	Subtype  string           `json:"subtype,omitempty"`
+	Target   string           `json:"target"`
+	Type     RelationshipType `json:"type,omitempty"`
+	Inferred bool             `json:"inferred,omitempty"`
+}
+
+// Graph is an identity graph of persons and their identifiers, built from a Response
+type Graph struct {
+	Edges []GraphEdge `json:"edges"`
+	Nodes []GraphNode `json:"nodes"`
+	edges map[string]bool
+	nodes map[string]bool
+}
+
+// NewGraph will build the identity graph for the response (person, possible persons and
+// their relationships). Identifiers are de-duplicated by normalized value, so any
+// identifier shared by more than one person connects them.
+func NewGraph(response *Response, options GraphOptions) *Graph {
+	g := &Graph{
+		Edges: []GraphEdge{},
+		Nodes: []GraphNode{},
+		edges: make(map[string]bool),
+		nodes: make(map[string]bool),
+	}
+	if response == nil {
+		return g
+	}
+
+	// The (matched) person
+	if !response.Person.isEmpty() {
+		g.addPerson(&response.Person, "person", options)
+	}
+
+	// All the possible persons
+	for index := range response.PossiblePersons {
+		g.addPerson(&response.PossiblePersons[index], "possible:"+strconv.Itoa(index), options)
+	}
+	return g
+}
+
+// addPerson adds a person, its identifiers and its relationships (recursively)
+func (g *Graph) addPerson(person *Person, fallbackID string, options GraphOptions) string {
+	if person.Inferred && !options.IncludeInferred {
+		return ""
+	}
+	id := graphPersonID(person.ID, person.SearchPointer, fallbackID)
+	g.addNode(GraphNode{ID: id, Kind: GraphNodePerson, Label: graphPersonLabel(person.Names, fallbackID), Inferred: person.Inferred})
+	g.addIdentifiers(id, person.Emails, person.Phones, person.Addresses, person.Usernames, options)
+	for index := range person.Relationships {
+		g.addRelationship(id, &person.Relationships[index], graphRelationshipID(id, index), options)
+	}
+	return id
+}
+
+// addRelationship adds a related person, its identifiers and its relationships (recursively)
+func (g *Graph) addRelationship(fromID string, relationship *Relationship, fallbackID string, options GraphOptions) {
+	if relationship.Inferred && !options.IncludeInferred {
+		return
+	}
+	id := graphPersonID("", relationship.SearchPointer, fallbackID)
+	g.addNode(GraphNode{ID: id, Kind: GraphNodePerson, Label: graphPersonLabel(relationship.Names, fallbackID), Inferred: relationship.Inferred})
+	g.addEdge(GraphEdge{
+		Inferred: relationship.Inferred,
+		Kind:     GraphEdgeRelationship,
+		Source:   fromID,
+		Subtype:  relationship.Subtype,
+		Target:   id,
+		Type:     relationship.Type,
+	})
+	g.addIdentifiers(id, relationship.Emails, relationship.Phones, relationship.Addresses, relationship.Usernames, options)
+	for index := range relationship.Relationships {
+		g.addRelationship(id, &relationship.Relationships[index], graphRelationshipID(id, index), options)
+	}
+}
+
+// addIdentifiers adds the identifier nodes (and edges to the person)
+func (g *Graph) addIdentifiers(personID string, emails []Email, phones []Phone, addresses []Address,
+	usernames []Username, options GraphOptions,
+) {
+	for _, email := range emails {
+		g.addIdentifier(personID, GraphNodeEmail, strings.ToLower(strings.TrimSpace(email.Address)), email.Address, email.Inferred, options)
+	}
+	for _, phone := range phones {
+		g.addIdentifier(personID, GraphNodePhone, phoneKey(&phone), graphPhoneLabel(phone), phone.Inferred, options)
+	}
+	for _, address := range addresses {
+		label := graphAddressLabel(address)
+		g.addIdentifier(personID, GraphNodeAddress, strings.ToLower(label), label, address.Inferred, options)
+	}
+	for _, username := range usernames {
+		g.addIdentifier(personID, GraphNodeUsername, strings.ToLower(strings.TrimSpace(username.Content)), username.Content, username.Inferred, options)
+	}
+}
+
+// addIdentifier adds a single identifier node (de-duplicated by value) and the edge to the person
+func (g *Graph) addIdentifier(personID string, kind GraphNodeKind, value, label string, inferred bool,
+	options GraphOptions,
+) {
+	if len(value) == 0 || (inferred && !options.IncludeInferred) {
+		return
+	}
+	id := string(kind) + ":" + value
+	g.addNode(GraphNode{ID: id, Kind: kind, Label: label, Inferred: inferred})
+	g.addEdge(GraphEdge{Inferred: inferred, Kind: GraphEdgeIdentifier, Source: personID, Target: id})
+}
+
+// addNode adds the node (if not already added)
+func (g *Graph) addNode(node GraphNode) {
+	if g.nodes[node.ID] {
+		return
+	}
+	g.nodes[node.ID] = true
+	g.Nodes = append(g.Nodes, node)
+}
+
+// addEdge adds the edge (if not already added)
+func (g *Graph) addEdge(edge GraphEdge) {
+	key := strings.Join([]string{string(edge.Kind), edge.Source, edge.Target, string(edge.Type), edge.Subtype}, "|")
+	if g.edges[key] {
+		return
+	}
+	g.edges[key] = true
+	edge.ID = "e" + strconv.Itoa(len(g.Edges))
+	g.Edges = append(g.Edges, edge)
+}
+
+// WriteDOT writes the graph in the Graphviz DOT format
+func (g *Graph) WriteDOT(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("digraph pipl {\n")
+	for _, node := range g.Nodes {
+		fmt.Fprintf(&b, "  %s [label=%s, kind=%s, shape=%s",
+			dotQuote(node.ID), dotQuote(node.Label), dotQuote(string(node.Kind)), dotShape(node.Kind))
+		if node.Inferred {
+			b.WriteString(", style=dashed")
+		}
+		b.WriteString("];\n")
+	}
+	for _, edge := range g.Edges {
+		fmt.Fprintf(&b, "  %s -> %s [kind=%s", dotQuote(edge.Source), dotQuote(edge.Target), dotQuote(string(edge.Kind)))
+		if label := graphEdgeLabel(edge); len(label) > 0 {
+			fmt.Fprintf(&b, ", label=%s", dotQuote(label))
+		}
+		if edge.Inferred {
+			b.WriteString(", style=dashed")
+		}
+		b.WriteString("];\n")
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// graphML is the GraphML document (http://graphml.graphdrawing.org/)
+type graphML struct {
+	XMLName xml.Name     `xml:"graphml"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   graphMLGraph `xml:"graph"`
+}
+
+// graphMLKey declares a data attribute
+type graphMLKey struct {
+	ID       string `xml:"id,attr"`
+	For      string `xml:"for,attr"`
+	AttrName string `xml:"attr.name,attr"`
+	AttrType string `xml:"attr.type,attr"`
+}
+
+// graphMLGraph holds the nodes and edges
+type graphMLGraph struct {
+	ID          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+// graphMLNode is a single node
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+// graphMLEdge is a single edge
+type graphMLEdge struct {
+	ID     string        `xml:"id,attr"`
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+// graphMLData is a data attribute value
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// WriteGraphML writes the graph in the GraphML (XML) format
+func (g *Graph) WriteGraphML(w io.Writer) error {
+	doc := graphML{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []graphMLKey{
+			{ID: "kind", For: "node", AttrName: "kind", AttrType: "string"},
+			{ID: "label", For: "node", AttrName: "label", AttrType: "string"},
+			{ID: "inferred", For: "node", AttrName: "inferred", AttrType: "boolean"},
+			{ID: "edge_kind", For: "edge", AttrName: "kind", AttrType: "string"},
+			{ID: "type", For: "edge", AttrName: "type", AttrType: "string"},
+			{ID: "subtype", For: "edge", AttrName: "subtype", AttrType: "string"},
+			{ID: "edge_inferred", For: "edge", AttrName: "inferred", AttrType: "boolean"},
+		},
+		Graph: graphMLGraph{ID: "pipl", EdgeDefault: "directed"},
+	}
+	for _, node := range g.Nodes {
+		doc.Graph.Nodes = append(doc.Graph.Nodes, graphMLNode{ID: node.ID, Data: []graphMLData{
+			{Key: "kind", Value: string(node.Kind)},
+			{Key: "label", Value: node.Label},
+			{Key: "inferred", Value: strconv.FormatBool(node.Inferred)},
+		}})
+	}
+	for _, edge := range g.Edges {
+		data := []graphMLData{{Key: "edge_kind", Value: string(edge.Kind)}}
+		if len(edge.Type) > 0 {
+			data = append(data, graphMLData{Key: "type", Value: string(edge.Type)})
+		}
+		if len(edge.Subtype) > 0 {
+			data = append(data, graphMLData{Key: "subtype", Value: edge.Subtype})
+		}
+		data = append(data, graphMLData{Key: "edge_inferred", Value: strconv.FormatBool(edge.Inferred)})
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge{ID: edge.ID, Source: edge.Source, Target: edge.Target, Data: data})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// cytoscapeElement is a single Cytoscape.js element (node or edge)
+type cytoscapeElement struct {
+	Data cytoscapeData `json:"data"`
+}
+
+// cytoscapeData is the data of a Cytoscape.js element
+type cytoscapeData struct {
+	ID       string           `json:"id"`
+	Kind     string           `json:"kind"`
+	Label    string           `json:"label,omitempty"`
+	Source   string           `json:"source,omitempty"`
+	Subtype  string           `json:"subtype,omitempty"`
+	Target   string           `json:"target,omitempty"`
+	Type     RelationshipType `json:"type,omitempty"`
+	Inferred bool             `json:"inferred"`
+}
+
+// CytoscapeJSON returns the graph in the Cytoscape.js JSON format: {"elements": {"nodes": [...], "edges": [...]}}
+func (g *Graph) CytoscapeJSON() ([]byte, error) {
+	elements := struct {
+		Edges []cytoscapeElement `json:"edges"`
+		Nodes []cytoscapeElement `json:"nodes"`
+	}{
+		Edges: make([]cytoscapeElement, 0, len(g.Edges)),
+		Nodes: make([]cytoscapeElement, 0, len(g.Nodes)),
+	}
+	for _, node := range g.Nodes {
+		elements.Nodes = append(elements.Nodes, cytoscapeElement{Data: cytoscapeData{
+			ID: node.ID, Kind: string(node.Kind), Label: node.Label, Inferred: node.Inferred,
+		}})
+	}
+	for _, edge := range g.Edges {
+		elements.Edges = append(elements.Edges, cytoscapeElement{Data: cytoscapeData{
+			ID: edge.ID, Kind: string(edge.Kind), Label: graphEdgeLabel(edge), Source: edge.Source,
+			Subtype: edge.Subtype, Target: edge.Target, Type: edge.Type, Inferred: edge.Inferred,
+		}})
+	}
+	return json.Marshal(map[string]any{"elements": elements})
+}
+
+// isEmpty returns true if the person has no data at all
+func (p *Person) isEmpty() bool {
+	return len(p.ID) == 0 && len(p.SearchPointer) == 0 && len(p.Names) == 0 && len(p.Emails) == 0 &&
+		len(p.Phones) == 0 && len(p.Addresses) == 0 && len(p.Usernames) == 0 && len(p.Relationships) == 0
+}
+
+// graphPersonID returns the node ID for a person (by ID, search pointer, or fallback)
+func graphPersonID(id GUID, searchPointer, fallbackID string) string {
+	switch {
+	case len(id) > 0:
+		return "person:" + string(id)
+	case len(searchPointer) > 0:
+		return "person:" + searchPointer
+	default:
+		return "person:" + fallbackID
+	}
+}
+
+// graphRelationshipID returns the fallback ID for a related person (by position under the person)
+func graphRelationshipID(personID string, index int) string {
+	return strings.TrimPrefix(personID, "person:") + ":relationship:" + strconv.Itoa(index)
+}
+
+// graphPersonLabel returns the label for a person (first usable name)
+func graphPersonLabel(names []Name, fallback string) string {
+	for _, name := range names {
+		switch {
+		case len(name.Display) > 0:
+			return name.Display
+		case len(name.First) > 0 || len(name.Last) > 0:
+			return strings.TrimSpace(name.First + " " + name.Last)
+		case len(name.Raw) > 0:
+			return name.Raw
+		}
+	}
+	return fallback
+}
+
+// graphPhoneLabel returns the label of a phone
+func graphPhoneLabel(phone Phone) string {
+	switch {
+	case len(phone.DisplayInternational) > 0:
+		return phone.DisplayInternational
+	case len(phone.Display) > 0:
+		return phone.Display
+	default:
+		return cmp.Or(phone.E164(), strings.TrimSpace(phone.Raw))
+	}
+}
+
+// graphAddressLabel returns the label of an address
+func graphAddressLabel(address Address) string {
+	if len(address.Display) > 0 {
+		return address.Display
+	}
+	if len(address.Raw) > 0 {
+		return address.Raw
+	}
+	parts := make([]string, 0, 4)
+	if street := strings.TrimSpace(address.House + " " + address.Street); len(street) > 0 {
+		parts = append(parts, street)
+	}
+	for _, part := range []string{address.City, address.State, address.Country} {
+		if len(part) > 0 {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// graphEdgeLabel returns the label of an edge (relationship type and subtype)
+func graphEdgeLabel(edge GraphEdge) string {
+	switch {
+	case len(edge.Type) > 0 && len(edge.Subtype) > 0:
+		return string(edge.Type) + ": " + edge.Subtype
+	case len(edge.Type) > 0:
+		return string(edge.Type)
+	default:
+		return edge.Subtype
+	}
+}
+
+// dotQuote quotes a DOT identifier or value
+func dotQuote(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value) + `"`
+}
+
+// dotShape returns the DOT shape for the kind of node
+func dotShape(kind GraphNodeKind) string {
+	switch kind {
+	case GraphNodePerson:
+		return "ellipse"
+	case GraphNodeEmail, GraphNodeUsername:
+		return "box"
+	case GraphNodePhone:
+		return "diamond"
+	case GraphNodeAddress:
+		return "house"
+	}
+	return "plaintext"
+}
@@ -0,0 +1,184 @@
+package pipl
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestGraphResponse returns two possible persons that share an email (and a related person)
+func newTestGraphResponse() *Response {
+	return &Response{
+		PossiblePersons: []Person{
+			{
+				ID:     "p1",
+				Names:  []Name{{Display: "Clark Kent"}},
+				Emails: []Email{{Address: "Clark@Example.com"}, {Address: "guess@example.com", Inferred: true}},
+				Phones: []Phone{{CountryCode: 1, Number: 9785550145, DisplayInternational: "+1 978-555-0145"}},
+				Relationships: []Relationship{
+					{Type: "friend", Subtype: "Best \"friend\"", Names: []Name{{First: "Lois", Last: "Lane"}}, Emails: []Email{{Address: "lois@example.com"}}},
+					{Type: "family", Inferred: true, Names: []Name{{First: "Kara"}}},
+				},
+			},
+			{
+				ID:        "p2",
+				Names:     []Name{{First: "Kal", Last: "El"}},
+				Emails:    []Email{{Address: "clark@example.com"}},
+				Usernames: []Username{{Content: "superman@facebook"}},
+				Addresses: []Address{{House: "10", Street: "Hickory Lane", City: "Smallville", State: "KS"}},
+			},
+		},
+	}
+}
+
+// TestNewGraph will test the method NewGraph()
+func TestNewGraph(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil response", func(t *testing.T) {
+		g := NewGraph(nil, GraphOptions{})
+		assert.Empty(t, g.Nodes)
+		assert.Empty(t, g.Edges)
+	})
+
+	t.Run("shared identifiers, no inferred", func(t *testing.T) {
+		g := NewGraph(newTestGraphResponse(), GraphOptions{})
+
+		ids := make([]string, 0, len(g.Nodes))
+		for _, node := range g.Nodes {
+			ids = append(ids, node.ID)
+		}
+		assert.Equal(t, []string{
+			"person:p1",
+			"email:clark@example.com",
+			"phone:+19785550145",
+			"person:p1:relationship:0",
+			"email:lois@example.com",
+			"person:p2",
+			"address:10 hickory lane, smallville, ks",
+			"username:superman@facebook",
+		}, ids)
+
+		// Both persons are linked to the same email
+		var linked []string
+		for _, edge := range g.Edges {
+			if edge.Target == "email:clark@example.com" {
+				linked = append(linked, edge.Source)
+			}
+		}
+		assert.Equal(t, []string{"person:p1", "person:p2"}, linked)
+
+		// Relationship edge is typed
+		assert.Contains(t, g.Edges, GraphEdge{
+			ID: g.Edges[2].ID, Kind: GraphEdgeRelationship, Source: "person:p1",
+			Target: "person:p1:relationship:0", Type: "friend", Subtype: "Best \"friend\"",
+		})
+	})
+
+	t.Run("include inferred", func(t *testing.T) {
+		g := NewGraph(newTestGraphResponse(), GraphOptions{IncludeInferred: true})
+		assert.Len(t, g.Nodes, 10)
+
+		var inferred int
+		for _, node := range g.Nodes {
+			if node.Inferred {
+				inferred++
+			}
+		}
+		assert.Equal(t, 2, inferred)
+	})
+
+	t.Run("inferred person", func(t *testing.T) {
+		response := newTestGraphResponse()
+		response.PossiblePersons = append(response.PossiblePersons, Person{
+			ID:       "p3",
+			Inferred: true,
+			Names:    []Name{{Display: "Clark Joseph Kent"}},
+			Emails:   []Email{{Address: "ckent@example.com"}},
+		})
+
+		g := NewGraph(response, GraphOptions{})
+		for _, node := range g.Nodes {
+			assert.NotEqual(t, "person:p3", node.ID)
+			assert.NotEqual(t, "email:ckent@example.com", node.ID)
+		}
+		for _, edge := range g.Edges {
+			assert.NotEqual(t, "person:p3", edge.Source)
+		}
+
+		g = NewGraph(response, GraphOptions{IncludeInferred: true})
+		assert.Contains(t, g.Nodes, GraphNode{ID: "person:p3", Kind: GraphNodePerson, Label: "Clark Joseph Kent", Inferred: true})
+	})
+
+	t.Run("success response", func(t *testing.T) {
+		response, err := loadResponseData("response_success.json")
+		require.NoError(t, err)
+
+		g := NewGraph(response, GraphOptions{})
+		assert.Equal(t, "person:f4a7d898-6fc1-4a24-b043-43eb292a6fd5", g.Nodes[0].ID)
+		assert.Equal(t, "Kal El", g.Nodes[0].Label)
+
+		var relationships int
+		for _, edge := range g.Edges {
+			if edge.Kind == GraphEdgeRelationship {
+				relationships++
+			}
+		}
+		assert.Equal(t, len(response.Person.Relationships), relationships)
+	})
+}
+
+// TestGraph_Export will test the graph exporters
+func TestGraph_Export(t *testing.T) {
+	t.Parallel()
+
+	g := NewGraph(newTestGraphResponse(), GraphOptions{})
+
+	t.Run("dot", func(t *testing.T) {
+		var b bytes.Buffer
+		require.NoError(t, g.WriteDOT(&b))
+		dot := b.String()
+		assert.Contains(t, dot, "digraph pipl {\n")
+		assert.Contains(t, dot, `"person:p1" [label="Clark Kent", kind="person", shape=ellipse];`)
+		assert.Contains(t, dot, `"person:p1" -> "person:p1:relationship:0" [kind="relationship", label="friend: Best \"friend\""];`)
+		assert.Contains(t, dot, `"person:p2" -> "email:clark@example.com" [kind="identifier"];`)
+	})
+
+	t.Run("graphml", func(t *testing.T) {
+		var b bytes.Buffer
+		require.NoError(t, g.WriteGraphML(&b))
+
+		var doc graphML
+		require.NoError(t, xml.Unmarshal(b.Bytes(), &doc))
+		assert.Len(t, doc.Graph.Nodes, len(g.Nodes))
+		assert.Len(t, doc.Graph.Edges, len(g.Edges))
+		assert.Equal(t, "person:p1", doc.Graph.Nodes[0].ID)
+		assert.Equal(t, "Clark Kent", doc.Graph.Nodes[0].Data[1].Value)
+	})
+
+	t.Run("cytoscape", func(t *testing.T) {
+		data, err := g.CytoscapeJSON()
+		require.NoError(t, err)
+
+		var doc struct {
+			Elements struct {
+				Nodes []struct {
+					Data map[string]any `json:"data"`
+				} `json:"nodes"`
+				Edges []struct {
+					Data map[string]any `json:"data"`
+				} `json:"edges"`
+			} `json:"elements"`
+		}
+		require.NoError(t, json.Unmarshal(data, &doc))
+		require.Len(t, doc.Elements.Nodes, len(g.Nodes))
+		require.Len(t, doc.Elements.Edges, len(g.Edges))
+		assert.Equal(t, "person:p1", doc.Elements.Nodes[0].Data["id"])
+		assert.Equal(t, "person", doc.Elements.Nodes[0].Data["kind"])
+		assert.Equal(t, "person:p1", doc.Elements.Edges[0].Data["source"])
+	})
+}
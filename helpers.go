@@ -2,6 +2,8 @@ package pipl
 
 import (
 	"fmt"
+	"net/url"
+	"strconv"
 	"strings"
 )
 
@@ -210,18 +212,37 @@ func (p *Person) AddURL(url string) error {
 	return nil
 }
 
+// AddImage appends an image (photo url) to the specified search object
+//
+// Source: https://docs.pipl.com/reference#image
+//
+// The url must be an http(s) url with a host (not inline image data)
+func (p *Person) AddImage(imageURL string) error {
+	// Must be an http(s) url
+	if parsed, err := url.Parse(imageURL); err != nil || len(parsed.Host) == 0 ||
+		(parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return ErrInvalidImageURL
+	}
+
+	// Set the image
+	newImage := new(Image)
+	newImage.URL = imageURL
+	p.Images = append(p.Images, *newImage)
+	return nil
+}
+
 // AddPhone appends a phone to the specified search object
 //
 // Source: https://docs.pipl.com/reference#phone
 //
-// Values are assumed to be sanitized/validated already
+// The length of the number is validated for the country calling code (see ParsePhoneNumber).
 //
 // Plan: Landline phones are available in all plans, mobile phones
 //
 //	in the BUSINESS plan only.
 func (p *Person) AddPhone(phoneNumber int64, countryCode int) error {
-	// Phone is required (min length unknown)
-	if phoneNumber == 0 {
+	// Phone is required
+	if phoneNumber <= 0 {
 		return ErrInvalidPhoneNumber
 	}
 
@@ -231,11 +252,14 @@ func (p *Person) AddPhone(phoneNumber int64, countryCode int) error {
 		return ErrMissingCountryCode
 	}
 
+	// Validate the length for the country (and set the display formats)
+	phone, err := newPhone(countryCode, strconv.FormatInt(phoneNumber, 10), 0)
+	if err != nil {
+		return err
+	}
+
 	// Set phone
-	newPhone := new(Phone)
-	newPhone.Number = phoneNumber
-	newPhone.CountryCode = countryCode
-	p.Phones = append(p.Phones, *newPhone)
+	p.Phones = append(p.Phones, *phone)
 	return nil
 }
 
@@ -243,21 +267,22 @@ func (p *Person) AddPhone(phoneNumber int64, countryCode int) error {
 //
 // Source:https://docs.pipl.com/reference#phone
 //
-// Values are assumed to be sanitized/validated already
+// The number is validated and normalized to E.164 (see ParsePhoneNumber). National numbers
+// (without "+" or "00") are validated for the DefaultCountryCode, for example "(978) 555-0145"
+// is "+19785550145", other countries need the country calling code.
 //
 // Plan: Landline phones are available in all plans, mobile phones
 //
 //	in the BUSINESS plan only.
 func (p *Person) AddPhoneRaw(phoneNumber string) error {
-	// Phone is required (min length unknown)
-	if len(phoneNumber) < 4 {
-		return ErrInvalidPhoneNumber
+	// Parsed and normalized to E.164 (national numbers are for the default country)
+	phone, err := ParsePhoneNumber(phoneNumber, DefaultCountryCode)
+	if err != nil {
+		return err
 	}
 
 	// Set the phone
-	newPhone := new(Phone)
-	newPhone.Raw = phoneNumber
-	p.Phones = append(p.Phones, *newPhone)
+	p.Phones = append(p.Phones, *phone)
 	return nil
 }
 
@@ -476,6 +501,11 @@ func (p *Person) AddOriginCountry(countryCode string) error {
 func (p *Person) AddRelationship(relationship Relationship) (err error) {
 	// todo: missing validations
 
+	// Invalid relationship type (empty is the default: friend)
+	if len(relationship.Type) > 0 && !relationship.Type.IsValid() {
+		return ErrInvalidRelationshipType
+	}
+
 	// Set relationship
 	p.Relationships = append(p.Relationships, relationship)
 	return err
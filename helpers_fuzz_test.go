@@ -1,6 +1,8 @@
 package pipl
 
 import (
+	"errors"
+	"strconv"
 	"strings"
 	"testing"
 )
@@ -276,23 +278,25 @@ func FuzzAddPhone(f *testing.F) {
 		person := NewPerson()
 		err := person.AddPhone(phoneNumber, countryCode)
 
-		if phoneNumber == 0 || countryCode == 0 {
+		if phoneNumber <= 0 || countryCode == 0 {
 			if err == nil {
 				t.Errorf("Expected error for phone: %d, country: %d", phoneNumber, countryCode)
 			}
-		} else {
-			if err != nil {
-				t.Errorf("Unexpected error for phone: %d, country: %d, error: %v", phoneNumber, countryCode, err)
+		} else if _, known := phonePlans[countryCode]; !known {
+			if !errors.Is(err, ErrUnknownCountryCode) {
+				t.Errorf("Expected unknown country code for phone: %d, country: %d, error: %v", phoneNumber, countryCode, err)
+			}
+		} else if err == nil {
+			if len(person.Phones) != 1 {
+				t.Errorf("Expected 1 phone, got %d", len(person.Phones))
 			} else {
-				if len(person.Phones) != 1 {
-					t.Errorf("Expected 1 phone, got %d", len(person.Phones))
-				} else {
-					phone := person.Phones[0]
-					if phone.Number != phoneNumber || phone.CountryCode != countryCode {
-						t.Errorf("Phone not set correctly")
-					}
+				phone := person.Phones[0]
+				if phone.Number != phoneNumber || phone.CountryCode != countryCode || len(phone.DisplayInternational) == 0 {
+					t.Errorf("Phone not set correctly")
 				}
 			}
+		} else if !errors.Is(err, ErrInvalidPhoneLength) {
+			t.Errorf("Unexpected error for phone: %d, country: %d, error: %v", phoneNumber, countryCode, err)
 		}
 	})
 }
@@ -316,16 +320,20 @@ func FuzzAddPhoneRaw(f *testing.F) {
 			if err == nil {
 				t.Errorf("Expected error for short phone: %q", phoneNumber)
 			}
-		} else {
-			if err != nil {
-				t.Errorf("Unexpected error for phone: %q, error: %v", phoneNumber, err)
-			} else {
-				if len(person.Phones) != 1 {
-					t.Errorf("Expected 1 phone, got %d", len(person.Phones))
-				} else if person.Phones[0].Raw != phoneNumber {
-					t.Errorf("Phone raw not set correctly")
+		} else if err == nil {
+			if len(person.Phones) != 1 {
+				t.Errorf("Expected 1 phone, got %d", len(person.Phones))
+			} else if phone := person.Phones[0]; phone.CountryCode != 0 {
+				// International numbers are normalized to E.164
+				if phone.Raw != phone.E164() && !strings.HasPrefix(phone.Raw, "+"+strconv.Itoa(phone.CountryCode)+"0") {
+					t.Errorf("Phone not normalized to E.164: %q", phone.Raw)
 				}
+			} else if digits, extension, _, _ := splitPhoneNumber(phoneNumber); phone.Raw != digits || phone.Extension != extension {
+				// National numbers are parsed into digits and the extension
+				t.Errorf("Phone raw not set correctly")
 			}
+		} else if len(person.Phones) != 0 {
+			t.Errorf("Phone added with error: %v", err)
 		}
 	})
 }
@@ -309,7 +309,7 @@ func TestAddPhoneRaw(t *testing.T) {
 		err := person.AddPhoneRaw(testPhoneRaw)
 		require.NoError(t, err)
 		require.NotEmpty(t, person.Phones)
-		require.Equal(t, testPhoneRaw, person.Phones[0].Raw)
+		require.Equal(t, "+"+testPhoneRaw, person.Phones[0].Raw)
 	})
 }
 
@@ -318,7 +318,7 @@ func ExamplePerson_AddPhoneRaw() {
 	person := NewPerson()
 	_ = person.AddPhoneRaw(testPhoneRaw)
 	fmt.Println(person.Phones[0].Raw)
-	// Output:19785550145
+	// Output:+19785550145
 }
 
 // BenchmarkAddPhoneRaw benchmarks the AddPhoneRaw method
@@ -765,7 +765,14 @@ func TestAddRelationship(t *testing.T) {
 			Inferred:    false,
 		})
 		require.NoError(t, err)
-		assert.Equal(t, "friend", person.Relationships[0].Type)
+		assert.Equal(t, RelationshipTypeFriend, person.Relationships[0].Type)
+	})
+
+	t.Run("invalid relationship type", func(t *testing.T) {
+		person := NewPerson()
+		err := person.AddRelationship(Relationship{Type: "freind"})
+		require.ErrorIs(t, err, ErrInvalidRelationshipType)
+		assert.Empty(t, person.Relationships)
 	})
 }
 
@@ -822,6 +829,42 @@ func BenchmarkAddURL(b *testing.B) {
 	}
 }
 
+// TestAddImage test adding an image to a person object
+func TestAddImage(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid urls", func(t *testing.T) {
+		person := NewPerson()
+		for _, imageURL := range []string{"", "https://", "ftp://example.com/photo.jpg", "data:image/jpeg;base64,MIIE", "MIIE"} {
+			require.ErrorIs(t, person.AddImage(imageURL), ErrInvalidImageURL)
+		}
+		assert.Empty(t, person.Images)
+	})
+
+	t.Run("valid url", func(t *testing.T) {
+		person := NewPerson()
+		require.NoError(t, person.AddImage(testImage))
+		require.Len(t, person.Images, 1)
+		assert.Equal(t, testImage, person.Images[0].URL)
+	})
+}
+
+// ExamplePerson_AddImage example using AddImage()
+func ExamplePerson_AddImage() {
+	person := NewPerson()
+	_ = person.AddImage("https://example.com/clark.jpg")
+	fmt.Println(person.Images[0].URL)
+	// Output:https://example.com/clark.jpg
+}
+
+// BenchmarkAddImage benchmarks the AddImage method
+func BenchmarkAddImage(b *testing.B) {
+	person := NewPerson()
+	for i := 0; i < b.N; i++ {
+		_ = person.AddImage(testImage)
+	}
+}
+
 // TestPerson_ProcessThumbnails test processing images for thumbnails
 func TestPerson_ProcessThumbnails(t *testing.T) {
 	t.Parallel()
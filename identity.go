@@ -0,0 +1,185 @@
+package pipl
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// ParseNote explains a fragment of the text that ParseIdentity() found ambiguous or dropped
+//
+// DO NOT CHANGE ORDER - Optimized for memory (malign)
+type ParseNote struct {
+	Err     error  `json:"-"`       // Validation error of the Add method (if the value was dropped)
+	Field   string `json:"field"`   // Person field, for example "emails" (empty for unrecognized text)
+	Message string `json:"message"` // Explanation
+	Text    string `json:"text"`    // Fragment of the text
+}
+
+// Patterns used by ParseIdentity(), in the order they are applied
+var (
+	identityURLPattern       = regexp.MustCompile(`(?i)\b(?:https?://|www\.)[^\s<>"',;]+`)                        //nolint:gochecknoglobals // Compiled once
+	identityEmailPattern     = regexp.MustCompile(`(?i)[a-z0-9._%+\-]+@[a-z0-9\-]+(?:\.[a-z0-9\-]+)*\.[a-z]{2,}`) //nolint:gochecknoglobals // Compiled once
+	identityHandleOnPattern  = regexp.MustCompile(`(?i)(?:^|[^\w.])@([\w.\-]+)\s+on\s+([\w.\-]+)`)                //nolint:gochecknoglobals // Compiled once
+	identityUsernamePattern  = regexp.MustCompile(`(?i)\b([\w.\-]+)@([a-z0-9]+)\b`)                               //nolint:gochecknoglobals // Compiled once
+	identityHandlePattern    = regexp.MustCompile(`(?:^|[^\w.])@([\w.\-]+)`)                                      //nolint:gochecknoglobals // Compiled once
+	identityPhonePattern     = regexp.MustCompile(`\+?\(?\d[\d\s().\-]{5,}\d`)                                    //nolint:gochecknoglobals // Compiled once
+	identityDatePattern      = regexp.MustCompile(`^\s*\d{4}-\d{2}-\d{2}\s*$`)                                    //nolint:gochecknoglobals // Compiled once
+	identitySeparatorPattern = regexp.MustCompile(`[,;|<>()\[\]\n\r\t]+`)                                         //nolint:gochecknoglobals // Compiled once
+	identityNamePattern      = regexp.MustCompile(`^\p{L}[\p{L}.'\-]*(?:\s+\p{L}[\p{L}.'\-]*)*$`)                 //nolint:gochecknoglobals // Compiled once
+)
+
+// Limits used by ParseIdentity()
+const (
+	identityNameMinWords = 2  // Minimum words of a name
+	identityNameMaxWords = 4  // Maximum words of a name
+	identityPhoneMin     = 7  // Minimum digits of a phone number
+	identityPhoneMax     = 15 // Maximum digits of a phone number (E.164)
+)
+
+// ParseIdentity will parse free text (for example "Jane Q. Doe <jane@acme.com>, +1 (555) 010-2000,
+// @janedoe on github") into a search person, using the Add methods (AddEmail(), AddPhoneRaw(),
+// AddURL(), AddUsername() and AddNameRaw()).
+//
+// Usernames are "user@provider" or "@user on provider" with a provider in AllowedServiceProviders.
+// The notes explain every fragment that is ambiguous (for example, a phone without a country code,
+// see AddPhoneRaw) or dropped (for example, a value that fails validation or text that is not recognized).
+func ParseIdentity(text string) (*Person, []ParseNote) {
+	parser := &identityParser{person: NewPerson(), seen: make(map[string]bool)}
+
+	text = identityURLPattern.ReplaceAllStringFunc(text, parser.url)
+	text = identityEmailPattern.ReplaceAllStringFunc(text, parser.email)
+	text = identityHandleOnPattern.ReplaceAllStringFunc(text, func(match string) string {
+		groups := identityHandleOnPattern.FindStringSubmatch(match)
+		return parser.username(strings.TrimSpace(match), groups[1], groups[2])
+	})
+	text = identityUsernamePattern.ReplaceAllStringFunc(text, func(match string) string {
+		groups := identityUsernamePattern.FindStringSubmatch(match)
+		return parser.username(match, groups[1], groups[2])
+	})
+	text = identityHandlePattern.ReplaceAllStringFunc(text, parser.handle)
+	text = identityPhonePattern.ReplaceAllStringFunc(text, parser.phone)
+
+	for _, fragment := range identitySeparatorPattern.Split(text, -1) {
+		parser.fragment(fragment)
+	}
+	return parser.person, parser.notes
+}
+
+// identityParser holds the person and notes of ParseIdentity()
+type identityParser struct {
+	notes  []ParseNote
+	person *Person
+	seen   map[string]bool // Values already added, by field and lower case value
+}
+
+// note records a note
+func (p *identityParser) note(field, text, message string, err error) {
+	p.notes = append(p.notes, ParseNote{Err: err, Field: field, Message: message, Text: text})
+}
+
+// add records the error of an Add method, returns the separator that replaces the match
+func (p *identityParser) add(field, text string, err error) string {
+	if err != nil {
+		p.note(field, text, "dropped: "+err.Error(), err)
+	}
+	return ","
+}
+
+// duplicate returns true (and records a note for the text) if the value (by key) was already added
+func (p *identityParser) duplicate(field, key, text string) bool {
+	key = field + ":" + strings.ToLower(key)
+	if p.seen[key] {
+		p.note(field, text, "duplicate, dropped", nil)
+		return true
+	}
+	p.seen[key] = true
+	return false
+}
+
+// url adds a url
+func (p *identityParser) url(match string) string {
+	value := strings.TrimRight(match, ".:!?")
+	if p.duplicate("urls", value, value) {
+		return ","
+	}
+	return p.add("urls", value, p.person.AddURL(value))
+}
+
+// email adds an email address
+func (p *identityParser) email(match string) string {
+	if p.duplicate("emails", match, match) {
+		return ","
+	}
+	return p.add("emails", match, p.person.AddEmail(match))
+}
+
+// username adds a username with a service provider
+func (p *identityParser) username(match, username, serviceProvider string) string {
+	serviceProvider = strings.ToLower(serviceProvider)
+	if p.duplicate("usernames", username+"@"+serviceProvider, username+"@"+serviceProvider) {
+		return ","
+	}
+	return p.add("usernames", match, p.person.AddUsername(username, serviceProvider))
+}
+
+// handle records a username without a service provider
+func (p *identityParser) handle(match string) string {
+	p.note("usernames", strings.TrimSpace(match), "username without a service provider, dropped", nil)
+	return ","
+}
+
+// phone adds a raw phone number (dates are kept as text)
+func (p *identityParser) phone(match string) string {
+	if identityDatePattern.MatchString(match) {
+		return match
+	}
+	value := strings.TrimSpace(match)
+	var digits int
+	for _, r := range value {
+		if unicode.IsDigit(r) {
+			digits++
+		}
+	}
+	if digits < identityPhoneMin || digits > identityPhoneMax {
+		p.note("phones", value, "not a phone number (7 to 15 digits), dropped", nil)
+		return ","
+	}
+	key := phoneKey(&Phone{Raw: value})
+	if parsed, err := ParsePhoneNumber(value, DefaultCountryCode); err == nil {
+		key = phoneKey(parsed) // National numbers are for the default country (like AddPhoneRaw)
+	}
+	if p.duplicate("phones", key, value) {
+		return ","
+	}
+	if err := p.person.AddPhoneRaw(value); err != nil {
+		return p.add("phones", value, err)
+	}
+	if !strings.HasPrefix(value, "+") && !strings.HasPrefix(value, "00") {
+		p.note("phones", value, "no country code, parsed for the default country ("+DefaultCountry+")", nil)
+	}
+	return ","
+}
+
+// fragment adds the remaining text as a name, or records it as dropped
+func (p *identityParser) fragment(fragment string) {
+	words := strings.Fields(fragment)
+	if len(words) == 0 {
+		return
+	}
+	text := strings.Join(words, " ")
+	switch {
+	case !identityNamePattern.MatchString(text):
+		p.note("", text, "unrecognized text, dropped", nil)
+	case len(words) < identityNameMinWords:
+		p.note("names", text, "single word, not used as a name", nil)
+	case len(words) > identityNameMaxWords:
+		p.note("names", text, "too many words for a name, dropped", nil)
+	case p.duplicate("names", text, text):
+	default:
+		if p.person.HasName() {
+			p.note("names", text, "more than one name", nil)
+		}
+		p.add("names", text, p.person.AddNameRaw(text))
+	}
+}
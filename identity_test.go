@@ -0,0 +1,107 @@
+package pipl
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseIdentity will test parsing free text into a search person
+func TestParseIdentity(t *testing.T) {
+	t.Parallel()
+
+	t.Run("support agent paste", func(t *testing.T) {
+		person, notes := ParseIdentity("Jane Q. Doe <jane@acme.com>, +1 (555) 010-2000, @janedoe on github")
+		require.NotNil(t, person)
+		assert.Empty(t, notes)
+		require.Len(t, person.Names, 1)
+		assert.Equal(t, "Jane Q. Doe", person.Names[0].Raw)
+		require.Len(t, person.Emails, 1)
+		assert.Equal(t, "jane@acme.com", person.Emails[0].Address)
+		require.Len(t, person.Phones, 1)
+		assert.Equal(t, "+15550102000", person.Phones[0].Raw)
+		assert.Equal(t, 1, person.Phones[0].CountryCode)
+		require.Len(t, person.Usernames, 1)
+		assert.Equal(t, "janedoe@github", person.Usernames[0].Content)
+		assert.True(t, SearchMeetsMinimumCriteria(person))
+	})
+
+	t.Run("urls and usernames", func(t *testing.T) {
+		person, notes := ParseIdentity("https://example.com/clark. clarkkent@twitter; www.dailyplanet.com")
+		assert.Empty(t, notes)
+		require.Len(t, person.URLs, 2)
+		assert.Equal(t, "https://example.com/clark", person.URLs[0].URL)
+		assert.Equal(t, "www.dailyplanet.com", person.URLs[1].URL)
+		require.Len(t, person.Usernames, 1)
+		assert.Equal(t, "clarkkent@twitter", person.Usernames[0].Content)
+	})
+
+	t.Run("ambiguous and dropped", func(t *testing.T) {
+		person, notes := ParseIdentity("Clark, 978-555-0145, @superman, superman@krypton, 1986-06-18, 12345, " +
+			"clark@example.com, CLARK@example.com, Lois Lane, #1 fan")
+		require.Len(t, person.Names, 1)
+		assert.Equal(t, "Lois Lane", person.Names[0].Raw)
+		require.Len(t, person.Phones, 1)
+		require.Len(t, person.Emails, 1)
+		assert.Empty(t, person.Usernames)
+
+		messages := make(map[string]ParseNote)
+		for _, note := range notes {
+			messages[note.Text] = note
+		}
+		require.Len(t, messages, 8)
+		assert.Equal(t, "single word, not used as a name", messages["Clark"].Message)
+		assert.Equal(t, "phones", messages["978-555-0145"].Field)
+		assert.Contains(t, messages["978-555-0145"].Message, "no country code")
+		assert.Equal(t, "usernames", messages["@superman"].Field)
+		assert.ErrorIs(t, messages["superman@krypton"].Err, ErrServiceProviderNotAccepted)
+		assert.Equal(t, "unrecognized text, dropped", messages["1986-06-18"].Message)
+		assert.Equal(t, "unrecognized text, dropped", messages["12345"].Message)
+		assert.Equal(t, "duplicate, dropped", messages["CLARK@example.com"].Message)
+		assert.Equal(t, "unrecognized text, dropped", messages["#1 fan"].Message)
+	})
+
+	t.Run("phones are validated for the default country", func(t *testing.T) {
+		person, notes := ParseIdentity("978-555-0145; +1 (978) 555-0145; 555-0100")
+		require.Len(t, person.Phones, 1)
+		assert.Equal(t, "+19785550145", person.Phones[0].Raw)
+		require.Len(t, notes, 3)
+		assert.Contains(t, notes[0].Message, "no country code")
+		assert.Equal(t, ParseNote{Field: "phones", Message: "duplicate, dropped", Text: "+1 (978) 555-0145"}, notes[1])
+		assert.Equal(t, "555-0100", notes[2].Text)
+		require.ErrorIs(t, notes[2].Err, ErrInvalidPhoneLength)
+	})
+
+	t.Run("more than one name", func(t *testing.T) {
+		person, notes := ParseIdentity("Clark Kent; Kal El Jor")
+		require.Len(t, person.Names, 2)
+		require.Len(t, notes, 1)
+		assert.Equal(t, "more than one name", notes[0].Message)
+		assert.Equal(t, "Kal El Jor", notes[0].Text)
+	})
+
+	t.Run("empty text", func(t *testing.T) {
+		person, notes := ParseIdentity("  ,, ")
+		require.NotNil(t, person)
+		assert.Empty(t, notes)
+		assert.False(t, SearchMeetsMinimumCriteria(person))
+	})
+}
+
+// ExampleParseIdentity example using ParseIdentity()
+func ExampleParseIdentity() {
+	person, notes := ParseIdentity("Jane Q. Doe <jane@acme.com>, @janedoe on github, 978-555-0100")
+	fmt.Println(person.Names[0].Raw, person.Emails[0].Address, person.Usernames[0].Content)
+	fmt.Println(notes[0].Text, "-", notes[0].Message)
+	// Output:Jane Q. Doe jane@acme.com janedoe@github
+	// 978-555-0100 - no country code, parsed for the default country (US)
+}
+
+// BenchmarkParseIdentity benchmarks the method ParseIdentity()
+func BenchmarkParseIdentity(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, _ = ParseIdentity("Jane Q. Doe <jane@acme.com>, +1 (555) 010-2000, @janedoe on github")
+	}
+}
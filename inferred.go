@@ -0,0 +1,137 @@
+package pipl
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// InferredReport is the report of the inferred data removed by FilterInferred
+type InferredReport struct {
+	Removed []InferredRemoval `json:"removed"`
+}
+
+// InferredRemoval is a single removed (inferred) value or person
+//
+// DO NOT CHANGE ORDER - Optimized for memory (malign)
+type InferredRemoval struct {
+	Value       any     `json:"value"`       // The removed value (for example, an Email or a Person)
+	Path        string  `json:"path"`        // Path in the original response, for example "person.emails[1]"
+	Probability float32 `json:"probability"` // Probability of the inferred data (see FilterInferred)
+}
+
+// FilterInferred will remove (in place) the inferred data below the minimum probability from the
+// person and the possible persons, and report everything that was removed.
+//
+// Inferred persons (@inferred) have a probability, their @match. Inferred values (emails, phones,
+// etc. with @inferred) do not have a probability in the response, so their probability is 0,
+// and they are removed by any minimum probability above 0. Set SearchParameters.MinimumProbability
+// to also have the API filter inferred data.
+func (r *Response) FilterInferred(minimumProbability float32) InferredReport {
+	report := InferredReport{Removed: []InferredRemoval{}}
+
+	// The person
+	if r.Person.Inferred && r.Person.Match < minimumProbability {
+		report.add("person", r.Person, r.Person.Match)
+		r.Person = Person{}
+	} else {
+		report.Removed = append(report.Removed, r.Person.FilterInferred(minimumProbability).prefixed("person")...)
+	}
+
+	// The possible persons
+	possiblePersons := r.PossiblePersons[:0]
+	for index, person := range r.PossiblePersons {
+		path := "possible_persons[" + strconv.Itoa(index) + "]"
+		if person.Inferred && person.Match < minimumProbability {
+			report.add(path, person, person.Match)
+			continue
+		}
+		report.Removed = append(report.Removed, person.FilterInferred(minimumProbability).prefixed(path)...)
+		possiblePersons = append(possiblePersons, person)
+	}
+	if r.PossiblePersons != nil {
+		r.PossiblePersons = possiblePersons
+	}
+
+	return report
+}
+
+// FilterInferred will remove (in place) the inferred values of the person (see Response.FilterInferred).
+// Paths in the report are relative to the person, for example "emails[1]".
+func (p *Person) FilterInferred(minimumProbability float32) InferredReport {
+	report := InferredReport{Removed: []InferredRemoval{}}
+	if minimumProbability > 0 {
+		filterInferredFields(reflect.ValueOf(p).Elem(), "", &report)
+	}
+	return report
+}
+
+// filterInferredFields removes the inferred values (slices, pointers and structs) of the struct,
+// and filters the kept values recursively (for example, the emails of a relationship)
+func filterInferredFields(value reflect.Value, path string, report *InferredReport) {
+	for index := 0; index < value.NumField(); index++ {
+		field := value.Field(index)
+		structField := value.Type().Field(index)
+		if !structField.IsExported() || !field.CanSet() {
+			continue
+		}
+		name, _, _ := strings.Cut(structField.Tag.Get("json"), ",")
+		if len(name) == 0 || name == "-" {
+			continue
+		}
+		fieldPath := name
+		if len(path) > 0 {
+			fieldPath = path + "." + name
+		}
+
+		switch field.Kind() { //nolint:exhaustive // Only field values are filtered
+		case reflect.Slice:
+			if field.Type().Elem().Kind() != reflect.Struct || field.Len() == 0 {
+				continue
+			}
+			kept := reflect.MakeSlice(field.Type(), 0, field.Len())
+			for item := 0; item < field.Len(); item++ {
+				itemPath := fieldPath + "[" + strconv.Itoa(item) + "]"
+				if isInferred(field.Index(item)) {
+					report.add(itemPath, field.Index(item).Interface(), 0)
+					continue
+				}
+				filterInferredFields(field.Index(item), itemPath, report)
+				kept = reflect.Append(kept, field.Index(item))
+			}
+			field.Set(kept)
+		case reflect.Pointer:
+			if field.IsNil() || field.Elem().Kind() != reflect.Struct {
+				continue
+			}
+			if isInferred(field.Elem()) {
+				report.add(fieldPath, field.Elem().Interface(), 0)
+				field.Set(reflect.Zero(field.Type()))
+			}
+		case reflect.Struct:
+			if isInferred(field) {
+				report.add(fieldPath, field.Interface(), 0)
+				field.Set(reflect.Zero(field.Type()))
+			}
+		}
+	}
+}
+
+// isInferred returns true if the struct has an @inferred flag that is set
+func isInferred(value reflect.Value) bool {
+	inferred := value.FieldByName("Inferred")
+	return inferred.IsValid() && inferred.Kind() == reflect.Bool && inferred.Bool()
+}
+
+// add adds a removed value to the report
+func (r *InferredReport) add(path string, value any, probability float32) {
+	r.Removed = append(r.Removed, InferredRemoval{Path: path, Probability: probability, Value: value})
+}
+
+// prefixed returns the removed values with the path prefix
+func (r InferredReport) prefixed(prefix string) []InferredRemoval {
+	for index := range r.Removed {
+		r.Removed[index].Path = prefix + "." + r.Removed[index].Path
+	}
+	return r.Removed
+}
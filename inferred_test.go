@@ -0,0 +1,117 @@
+package pipl
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestInferredResponse returns a response with inferred values and an inferred possible person
+func newTestInferredResponse() *Response {
+	return &Response{
+		Person: Person{
+			DateOfBirth: &DateOfBirth{Display: "35 years old", Inferred: true},
+			Emails: []Email{
+				{Address: "clark@example.com"},
+				{Address: "guess@example.com", Inferred: true},
+			},
+			Relationships: []Relationship{
+				{Names: []Name{{Display: "Lois Lane"}}, Emails: []Email{{Address: "lois@example.com", Inferred: true}}},
+				{Names: []Name{{Display: "Jimmy Olsen"}}, Inferred: true},
+			},
+		},
+		PossiblePersons: []Person{
+			{Match: 0.4, Inferred: true, Names: []Name{{Display: "Kal El"}}},
+			{Match: 0.95, Inferred: true, Names: []Name{{Display: "Clark J Kent"}}},
+			{Match: 0.3, Names: []Name{{Display: "C Kent"}}},
+		},
+	}
+}
+
+// TestResponse_FilterInferred will test removing inferred data client-side
+func TestResponse_FilterInferred(t *testing.T) {
+	t.Parallel()
+
+	t.Run("remove inferred data", func(t *testing.T) {
+		response := newTestInferredResponse()
+		report := response.FilterInferred(0.9)
+
+		var paths []string
+		for _, removed := range report.Removed {
+			paths = append(paths, removed.Path)
+		}
+		assert.Equal(t, []string{
+			"person.emails[1]",
+			"person.relationships[0].emails[0]",
+			"person.relationships[1]",
+			"person.dob",
+			"possible_persons[0]",
+		}, paths)
+		assert.InDelta(t, 0.4, report.Removed[4].Probability, 0.001)
+		assert.Equal(t, "guess@example.com", report.Removed[0].Value.(Email).Address) //nolint:forcetypeassert // Test
+
+		// What is left
+		assert.Nil(t, response.Person.DateOfBirth)
+		require.Len(t, response.Person.Emails, 1)
+		require.Len(t, response.Person.Relationships, 1)
+		assert.Empty(t, response.Person.Relationships[0].Emails)
+		require.Len(t, response.PossiblePersons, 2)
+		assert.Equal(t, "Clark J Kent", response.PossiblePersons[0].Names[0].Display)
+		assert.Equal(t, "C Kent", response.PossiblePersons[1].Names[0].Display)
+	})
+
+	t.Run("zero probability keeps everything", func(t *testing.T) {
+		response := newTestInferredResponse()
+		report := response.FilterInferred(0)
+		assert.Empty(t, report.Removed)
+		assert.Len(t, response.Person.Emails, 2)
+		assert.Len(t, response.PossiblePersons, 3)
+	})
+
+	t.Run("inferred person", func(t *testing.T) {
+		response := &Response{Person: Person{Inferred: true, Match: 0.5, Names: []Name{{Display: "Kal El"}}}}
+		report := response.FilterInferred(0.6)
+		require.Len(t, report.Removed, 1)
+		assert.Equal(t, "person", report.Removed[0].Path)
+		assert.Empty(t, response.Person.Names)
+	})
+
+	t.Run("person paths are relative", func(t *testing.T) {
+		person := &Person{Phones: []Phone{{Raw: "555", Inferred: true}}}
+		report := person.FilterInferred(1)
+		require.Len(t, report.Removed, 1)
+		assert.Equal(t, "phones[0]", report.Removed[0].Path)
+		assert.Empty(t, person.Phones)
+	})
+}
+
+// TestClient_MinimumProbability will test sending the minimum probability
+func TestClient_MinimumProbability(t *testing.T) {
+	t.Parallel()
+
+	search := func(t *testing.T, probability float32) url.Values {
+		mock := &searchResponse{}
+		options := DefaultSearchOptions()
+		options.Search.MinimumProbability = probability
+		c := NewClient(WithAPIKey(testKey), WithHTTPClient(mock), WithSearchOptions(options))
+
+		person := NewPerson()
+		require.NoError(t, person.AddEmail(testEmail))
+		_, err := c.Search(context.Background(), person)
+		require.NoError(t, err)
+		return mock.lastForm.Load().(url.Values) //nolint:forcetypeassert,errcheck // Only url.Values are stored
+	}
+
+	t.Run("default is not sent", func(t *testing.T) {
+		form := search(t, MinimumProbability)
+		assert.False(t, form.Has(fieldMinimumProbability))
+	})
+
+	t.Run("custom probability is sent", func(t *testing.T) {
+		form := search(t, 0.5)
+		assert.Equal(t, "0.5", form.Get(fieldMinimumProbability))
+	})
+}
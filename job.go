@@ -0,0 +1,352 @@
+package pipl
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// jobRecordsFile is the append-only checkpoint file (one JSON record per line)
+	jobRecordsFile = "records.jsonl"
+
+	// jobSummaryFile is the final summary of the job
+	jobSummaryFile = "summary.json"
+)
+
+// JobStatus is the outcome of a single job record
+type JobStatus string
+
+const (
+	// JobStatusSuccess is when the search returned a person (or possible persons)
+	JobStatusSuccess JobStatus = "success"
+
+	// JobStatusNoMatch is when the search was successful, but no person was found
+	JobStatusNoMatch JobStatus = "no_match"
+
+	// JobStatusError is when the search failed
+	JobStatusError JobStatus = "error"
+)
+
+// JobRecord is the checkpointed result of a single input (by index)
+//
+// DO NOT CHANGE ORDER - Optimized for memory (malign)
+type JobRecord struct {
+	CompletedAt time.Time `json:"completed_at"`
+	Response    *Response `json:"response,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	Status      JobStatus `json:"status"`
+	Index       int       `json:"index"`
+}
+
+// JobSummary is the final summary of a job run (also saved as summary.json)
+//
+// DO NOT CHANGE ORDER - Optimized for memory (malign)
+type JobSummary struct {
+	FinishedAt time.Time `json:"finished_at"`
+	StartedAt  time.Time `json:"started_at"`
+	JobID      string    `json:"job_id"`
+	Errors     int       `json:"errors"`
+	NoMatches  int       `json:"no_matches"`
+	Resumed    int       `json:"resumed"`
+	Searched   int       `json:"searched"`
+	Successes  int       `json:"successes"`
+	Total      int       `json:"total"`
+}
+
+// JobOptions are the settings for running a job
+//
+// DO NOT CHANGE ORDER - Optimized for memory (malign)
+type JobOptions struct {
+	// Batch are the options for the searches (concurrency, timeouts, rate limits, etc.), FailFast is not used
+	Batch BatchOptions
+
+	// Directory is where the job checkpoints are stored (one sub-directory per job ID)
+	Directory string
+
+	// RetryErrors will search (and bill) the records that previously failed again
+	RetryErrors bool
+}
+
+// JobRunner runs resumable bulk searches, checkpointing every completed record to disk.
+// If the job is interrupted (deploys, restarts, etc.), running the same job ID again
+// resumes where it left off, without searching the completed records again.
+type JobRunner struct {
+	options JobOptions
+	service SearchService
+}
+
+// NewJobRunner will create a new job runner on top of the search service (client)
+func NewJobRunner(service SearchService, options JobOptions) *JobRunner {
+	return &JobRunner{
+		options: options,
+		service: service,
+	}
+}
+
+// Run will search every person from the input source and checkpoint the results under the job ID.
+// The input source must produce the same people in the same order every time the job is run,
+// as records are tracked by their index.
+//
+// Records interrupted by the context are not checkpointed (they are searched on the next run).
+// When all the records are completed, the summary is written to summary.json and returned.
+func (j *JobRunner) Run(ctx context.Context, jobID string, people iter.Seq[*Person]) (*JobSummary, error) {
+	summary := &JobSummary{JobID: jobID, StartedAt: time.Now().UTC()}
+
+	// Create (or re-open) the job directory
+	directory, err := j.jobDirectory(jobID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// Load the completed records
+	var records map[int]JobRecord
+	var file *os.File
+	if records, file, err = openJobRecords(filepath.Join(directory, jobRecordsFile)); err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	// Skip the completed records (failed records are searched again if RetryErrors is set)
+	completed := make(map[int]bool, len(records))
+	for index, record := range records {
+		completed[index] = record.Status != JobStatusError || !j.options.RetryErrors
+	}
+
+	// Only search the people that are not completed (index in the stream -> index in the input)
+	var (
+		indexes   []int
+		indexesMu sync.Mutex
+		total     int
+	)
+	remaining := func(yield func(*Person) bool) {
+		for person := range people {
+			index := total
+			total++
+			if completed[index] {
+				continue
+			}
+			indexesMu.Lock()
+			indexes = append(indexes, index)
+			indexesMu.Unlock()
+			if !yield(person) {
+				return
+			}
+		}
+	}
+
+	// Run the searches (every record is attempted), checkpoint as they complete
+	batchOptions := j.options.Batch
+	batchOptions.FailFast = false
+	for result := range SearchStream(ctx, j.service, remaining, StreamOptions{Batch: batchOptions}) {
+		if ctx.Err() != nil && result.Err != nil {
+			continue // interrupted, not completed
+		}
+
+		indexesMu.Lock()
+		record := newJobRecord(indexes[result.Index], result)
+		indexesMu.Unlock()
+
+		if err = appendJobRecord(file, record); err != nil {
+			return nil, err
+		}
+		records[record.Index] = record
+		summary.Searched++
+	}
+
+	// Stopped before completing the job
+	if err = ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Summarize the records
+	summary.Total = total
+	summary.Resumed = total - summary.Searched
+	for index := 0; index < total; index++ {
+		switch records[index].Status {
+		case JobStatusSuccess:
+			summary.Successes++
+		case JobStatusNoMatch:
+			summary.NoMatches++
+		case JobStatusError:
+			summary.Errors++
+		}
+	}
+	summary.FinishedAt = time.Now().UTC()
+
+	// Save the summary
+	var data []byte
+	if data, err = json.MarshalIndent(summary, "", "  "); err != nil {
+		return nil, err
+	}
+	if err = os.WriteFile(filepath.Join(directory, jobSummaryFile), data, 0o600); err != nil {
+		return nil, err
+	}
+
+	return summary, nil
+}
+
+// Records returns an iterator over the checkpointed records of the job (latest record per index, by index)
+func (j *JobRunner) Records(jobID string) iter.Seq2[JobRecord, error] {
+	return func(yield func(JobRecord, error) bool) {
+		directory, err := j.jobDirectory(jobID, false)
+		if err != nil {
+			yield(JobRecord{}, err)
+			return
+		}
+
+		var records map[int]JobRecord
+		if records, _, err = readJobRecords(filepath.Join(directory, jobRecordsFile)); err != nil {
+			yield(JobRecord{}, err)
+			return
+		}
+
+		indexes := make([]int, 0, len(records))
+		for index := range records {
+			indexes = append(indexes, index)
+		}
+		slices.Sort(indexes)
+		for _, index := range indexes {
+			if !yield(records[index], nil) {
+				return
+			}
+		}
+	}
+}
+
+// Summary returns the summary of a finished job
+func (j *JobRunner) Summary(jobID string) (*JobSummary, error) {
+	directory, err := j.jobDirectory(jobID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	if data, err = os.ReadFile(filepath.Join(directory, jobSummaryFile)); err != nil { //nolint:gosec // Path is built from a validated job ID
+		return nil, err
+	}
+	summary := new(JobSummary)
+	if err = json.Unmarshal(data, summary); err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// jobDirectory returns (and optionally creates) the directory for the job
+func (j *JobRunner) jobDirectory(jobID string, create bool) (string, error) {
+	// Job ID is used as a directory name
+	if len(jobID) == 0 || jobID == "." || jobID == ".." || strings.ContainsAny(jobID, `/\`) {
+		return "", ErrInvalidJobID
+	}
+
+	directory := filepath.Join(j.options.Directory, jobID)
+	if create {
+		if err := os.MkdirAll(directory, 0o750); err != nil {
+			return "", err
+		}
+	}
+	return directory, nil
+}
+
+// newJobRecord will create a record from a search result
+func newJobRecord(index int, result BatchResult) JobRecord {
+	record := JobRecord{CompletedAt: time.Now().UTC(), Index: index}
+	if result.Err != nil {
+		record.Error = result.Err.Error() // Also kept with the response (DecodeModeStrict)
+	}
+	switch {
+	case searchFailed(result.Response, result.Err):
+		record.Status = JobStatusError
+	case result.Response == nil || result.Response.PersonsCount == 0:
+		record.Status = JobStatusNoMatch
+		record.Response = result.Response
+	default:
+		record.Status = JobStatusSuccess
+		record.Response = result.Response
+	}
+	return record
+}
+
+// openJobRecords will read the records and open the file for appending new records.
+// A partially written (last) record, from a crash, is discarded.
+func openJobRecords(filename string) (map[int]JobRecord, *os.File, error) {
+	records, size, err := readJobRecords(filename)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, nil, err
+	}
+
+	var file *os.File
+	if file, err = os.OpenFile(filename, os.O_CREATE|os.O_WRONLY, 0o600); err != nil { //nolint:gosec // Path is built from a validated job ID
+		return nil, nil, err
+	}
+
+	// Remove the partial record (if any) and append after the last complete record
+	if err = file.Truncate(size); err == nil {
+		_, err = file.Seek(size, io.SeekStart)
+	}
+	if err != nil {
+		_ = file.Close()
+		return nil, nil, err
+	}
+	return records, file, nil
+}
+
+// readJobRecords will read all the complete records, and the size (in bytes) of the complete records
+func readJobRecords(filename string) (map[int]JobRecord, int64, error) {
+	records := make(map[int]JobRecord)
+	file, err := os.Open(filename) //nolint:gosec // Path is built from a validated job ID
+	if err != nil {
+		return records, 0, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	var size int64
+	reader := bufio.NewReader(file)
+	for {
+		line, readErr := reader.ReadBytes('\n')
+
+		// Only complete lines are records (a partial line is from an interrupted write)
+		if readErr == nil {
+			if trimmed := bytes.TrimSpace(line); len(trimmed) > 0 {
+				var record JobRecord
+				if err = json.Unmarshal(trimmed, &record); err != nil {
+					return nil, 0, fmt.Errorf("invalid job record at offset %d: %w", size, err)
+				}
+				records[record.Index] = record
+			}
+			size += int64(len(line))
+			continue
+		}
+		if errors.Is(readErr, io.EOF) {
+			return records, size, nil
+		}
+		return nil, 0, readErr
+	}
+}
+
+// appendJobRecord will append (and sync) a single record
+func appendJobRecord(file *os.File, record JobRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	if _, err = file.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	return file.Sync()
+}
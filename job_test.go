@@ -0,0 +1,152 @@
+package pipl
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestJobRunner will test the resumable job runner
+func TestJobRunner(t *testing.T) {
+	t.Parallel()
+
+	people := newTestPeople(t,
+		"one@example.com",
+		"two@example.com",
+		"three@"+testFailDomain,
+		"four@"+testNotFoundDomain,
+		"five@example.com",
+	)
+
+	t.Run("invalid job id", func(t *testing.T) {
+		runner := NewJobRunner(NewClient(WithHTTPClient(&searchResponse{})), JobOptions{Directory: t.TempDir()})
+		for _, jobID := range []string{"", ".", "..", "a/b", `a\b`} {
+			_, err := runner.Run(context.Background(), jobID, slices.Values(people))
+			require.ErrorIs(t, err, ErrInvalidJobID)
+		}
+	})
+
+	t.Run("complete job", func(t *testing.T) {
+		mock := &searchResponse{}
+		runner := NewJobRunner(NewClient(WithAPIKey(testKey), WithHTTPClient(mock)), JobOptions{
+			Batch:     BatchOptions{Concurrency: 3},
+			Directory: t.TempDir(),
+		})
+
+		summary, err := runner.Run(context.Background(), "nightly", slices.Values(people))
+		require.NoError(t, err)
+		assert.Equal(t, "nightly", summary.JobID)
+		assert.Equal(t, 5, summary.Total)
+		assert.Equal(t, 5, summary.Searched)
+		assert.Equal(t, 0, summary.Resumed)
+		assert.Equal(t, 3, summary.Successes)
+		assert.Equal(t, 1, summary.NoMatches)
+		assert.Equal(t, 1, summary.Errors)
+		assert.Equal(t, int32(5), mock.calls.Load())
+
+		// Saved summary
+		var saved *JobSummary
+		saved, err = runner.Summary("nightly")
+		require.NoError(t, err)
+		assert.Equal(t, summary.Successes, saved.Successes)
+		assert.Equal(t, summary.Total, saved.Total)
+
+		// Saved records
+		var statuses []JobStatus
+		for record, recordErr := range runner.Records("nightly") {
+			require.NoError(t, recordErr)
+			statuses = append(statuses, record.Status)
+		}
+		assert.Equal(t, []JobStatus{JobStatusSuccess, JobStatusSuccess, JobStatusError, JobStatusNoMatch, JobStatusSuccess}, statuses)
+
+		// Running again does not search again
+		summary, err = runner.Run(context.Background(), "nightly", slices.Values(people))
+		require.NoError(t, err)
+		assert.Equal(t, 5, summary.Resumed)
+		assert.Equal(t, 0, summary.Searched)
+		assert.Equal(t, int32(5), mock.calls.Load())
+	})
+
+	t.Run("resume after interruption", func(t *testing.T) {
+		directory := t.TempDir()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		mock := &searchResponse{}
+		runner := NewJobRunner(NewClient(WithAPIKey(testKey), WithHTTPClient(mock)), JobOptions{
+			Batch: BatchOptions{
+				Concurrency: 1,
+				OnProgress: func(progress BatchProgress) {
+					if progress.Completed == 2 {
+						cancel()
+					}
+				},
+			},
+			Directory: directory,
+		})
+
+		_, err := runner.Run(ctx, "restart", slices.Values(people))
+		require.ErrorIs(t, err, context.Canceled)
+
+		// Simulate a crash in the middle of writing a record
+		var file *os.File
+		file, err = os.OpenFile(filepath.Join(directory, "restart", jobRecordsFile), os.O_APPEND|os.O_WRONLY, 0o600) //nolint:gosec // Test file
+		require.NoError(t, err)
+		_, err = file.WriteString(`{"index":4,"sta`)
+		require.NoError(t, err)
+		require.NoError(t, file.Close())
+
+		// Resume the job
+		mock = &searchResponse{}
+		runner = NewJobRunner(NewClient(WithAPIKey(testKey), WithHTTPClient(mock)), JobOptions{Directory: directory})
+		var summary *JobSummary
+		summary, err = runner.Run(context.Background(), "restart", slices.Values(people))
+		require.NoError(t, err)
+		assert.Equal(t, 2, summary.Resumed)
+		assert.Equal(t, 3, summary.Searched)
+		assert.Equal(t, 3, summary.Successes)
+		assert.Equal(t, 1, summary.NoMatches)
+		assert.Equal(t, 1, summary.Errors)
+		assert.Equal(t, int32(3), mock.calls.Load())
+
+		// Records file is valid
+		var count int
+		for _, recordErr := range runner.Records("restart") {
+			require.NoError(t, recordErr)
+			count++
+		}
+		assert.Equal(t, 5, count)
+	})
+
+	t.Run("retry errors", func(t *testing.T) {
+		directory := t.TempDir()
+		mock := &searchResponse{}
+		client := NewClient(WithAPIKey(testKey), WithHTTPClient(mock))
+
+		_, err := NewJobRunner(client, JobOptions{Directory: directory}).Run(context.Background(), "retry", slices.Values(people))
+		require.NoError(t, err)
+
+		var summary *JobSummary
+		summary, err = NewJobRunner(client, JobOptions{Directory: directory, RetryErrors: true}).Run(context.Background(), "retry", slices.Values(people))
+		require.NoError(t, err)
+		assert.Equal(t, 1, summary.Searched)
+		assert.Equal(t, 1, summary.Errors)
+		assert.Equal(t, int32(6), mock.calls.Load())
+	})
+
+	t.Run("missing job", func(t *testing.T) {
+		runner := NewJobRunner(NewClient(WithHTTPClient(&searchResponse{})), JobOptions{Directory: t.TempDir()})
+
+		_, err := runner.Summary("missing")
+		require.ErrorIs(t, err, os.ErrNotExist)
+
+		for _, recordErr := range runner.Records("missing") {
+			require.ErrorIs(t, recordErr, os.ErrNotExist)
+		}
+	})
+}
@@ -0,0 +1,331 @@
+package pipl
+
+import (
+	"cmp"
+	"encoding/json"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// JSON-LD constants
+const (
+	// SchemaVocabulary is the schema.org vocabulary of the JSON-LD export
+	SchemaVocabulary = "https://schema.org/"
+
+	// SchemaPiplNamespace is the namespace of the "pipl:" extension properties (for example "pipl:validSince")
+	SchemaPiplNamespace = "https://docs.pipl.com/reference#"
+)
+
+// SchemaContext is the JSON-LD @context of the export
+type SchemaContext struct {
+	Vocab string `json:"@vocab"`
+	Pipl  string `json:"pipl"`
+}
+
+// SchemaPerson is a schema.org Person (https://schema.org/Person). Values with dates (@valid_since and
+// @last_seen) keep them as "pipl:" extension properties: inline for nodes (address, organizations and
+// relationships), and in "pipl:provenance" for text values (for example, an email).
+//
+// The field order is the (deterministic) JSON order
+type SchemaPerson struct {
+	Context          *SchemaContext        `json:"@context,omitempty"`
+	Type             string                `json:"@type"`
+	ID               string                `json:"@id,omitempty"`
+	Name             string                `json:"name,omitempty"`
+	HonorificPrefix  string                `json:"honorificPrefix,omitempty"`
+	GivenName        string                `json:"givenName,omitempty"`
+	AdditionalName   string                `json:"additionalName,omitempty"`
+	FamilyName       string                `json:"familyName,omitempty"`
+	HonorificSuffix  string                `json:"honorificSuffix,omitempty"`
+	AlternateName    []string              `json:"alternateName,omitempty"`
+	Gender           string                `json:"gender,omitempty"`
+	BirthDate        string                `json:"birthDate,omitempty"`
+	Email            []string              `json:"email,omitempty"`
+	Telephone        []string              `json:"telephone,omitempty"`
+	Address          []SchemaPostalAddress `json:"address,omitempty"`
+	JobTitle         []string              `json:"jobTitle,omitempty"`
+	WorksFor         []SchemaOrganization  `json:"worksFor,omitempty"`
+	AlumniOf         []SchemaOrganization  `json:"alumniOf,omitempty"`
+	KnowsLanguage    []string              `json:"knowsLanguage,omitempty"`
+	Image            []string              `json:"image,omitempty"`
+	URL              []string              `json:"url,omitempty"`
+	SameAs           []string              `json:"sameAs,omitempty"`
+	Knows            []SchemaPerson        `json:"knows,omitempty"`
+	RelationshipType RelationshipType      `json:"pipl:relationshipType,omitempty"`
+	Subtype          string                `json:"pipl:relationshipSubtype,omitempty"`
+	ValidSince       string                `json:"pipl:validSince,omitempty"`
+	LastSeen         string                `json:"pipl:lastSeen,omitempty"`
+	Provenance       []SchemaProvenance    `json:"pipl:provenance,omitempty"`
+}
+
+// SchemaPostalAddress is a schema.org PostalAddress (https://schema.org/PostalAddress)
+//
+// The field order is the (deterministic) JSON order
+type SchemaPostalAddress struct {
+	Type                string      `json:"@type"`
+	Name                string      `json:"name,omitempty"` // The address, if not parsed
+	StreetAddress       string      `json:"streetAddress,omitempty"`
+	PostOfficeBoxNumber string      `json:"postOfficeBoxNumber,omitempty"`
+	AddressLocality     string      `json:"addressLocality,omitempty"`
+	AddressRegion       string      `json:"addressRegion,omitempty"`
+	PostalCode          string      `json:"postalCode,omitempty"`
+	AddressCountry      string      `json:"addressCountry,omitempty"`
+	AddressType         AddressType `json:"pipl:type,omitempty"`
+	ValidSince          string      `json:"pipl:validSince,omitempty"`
+	LastSeen            string      `json:"pipl:lastSeen,omitempty"`
+}
+
+// SchemaOrganization is a schema.org Organization (worksFor) or EducationalOrganization (alumniOf)
+//
+// The field order is the (deterministic) JSON order
+type SchemaOrganization struct {
+	Type       string `json:"@type"`
+	Name       string `json:"name"`
+	JobTitle   string `json:"pipl:jobTitle,omitempty"` // The title of the job at the organization
+	Degree     string `json:"pipl:degree,omitempty"`   // The degree of the education at the organization
+	StartDate  string `json:"pipl:startDate,omitempty"`
+	EndDate    string `json:"pipl:endDate,omitempty"`
+	ValidSince string `json:"pipl:validSince,omitempty"`
+	LastSeen   string `json:"pipl:lastSeen,omitempty"`
+}
+
+// SchemaProvenance is the dates of a text value of the person (for example, an email)
+//
+// The field order is the (deterministic) JSON order
+type SchemaProvenance struct {
+	Property   string `json:"pipl:property"` // The schema.org property, for example "email"
+	Value      string `json:"pipl:value"`
+	ValidSince string `json:"pipl:validSince,omitempty"`
+	LastSeen   string `json:"pipl:lastSeen,omitempty"`
+}
+
+// JSONLD will encode the person as a schema.org Person in JSON-LD (see SchemaPerson)
+func (p *Person) JSONLD() ([]byte, error) {
+	return json.Marshal(p.SchemaPerson())
+}
+
+// JSONLD will encode the relationship as a schema.org Person in JSON-LD (see SchemaPerson)
+func (r *Relationship) JSONLD() ([]byte, error) {
+	return json.Marshal(r.SchemaPerson())
+}
+
+// SchemaPerson returns the person as a schema.org Person, with the JSON-LD @context
+func (p *Person) SchemaPerson() *SchemaPerson {
+	person := newSchemaPerson(p)
+	person.Context = &SchemaContext{Vocab: SchemaVocabulary, Pipl: SchemaPiplNamespace}
+	if len(p.ID) > 0 {
+		person.ID = "urn:uuid:" + string(p.ID)
+	}
+	return person
+}
+
+// SchemaPerson returns the relationship as a schema.org Person, with the JSON-LD @context
+func (r *Relationship) SchemaPerson() *SchemaPerson {
+	person := newSchemaRelationship(r)
+	person.Context = &SchemaContext{Vocab: SchemaVocabulary, Pipl: SchemaPiplNamespace}
+	return person
+}
+
+// newSchemaPerson will create the schema.org Person (without the @context)
+func newSchemaPerson(p *Person) *SchemaPerson {
+	person := &SchemaPerson{Type: "Person"}
+
+	// Names: the primary name, and the other names as alternate names
+	primary := p.PrimaryName()
+	if primary != nil {
+		person.Name = nameDisplay(primary)
+		person.HonorificPrefix = primary.Prefix
+		person.GivenName = primary.First
+		person.AdditionalName = primary.Middle
+		person.FamilyName = primary.Last
+		person.HonorificSuffix = primary.Suffix
+	}
+	for index := range p.Names {
+		if display := nameDisplay(&p.Names[index]); len(display) > 0 && display != person.Name {
+			person.AlternateName = appendUnique(person.AlternateName, display)
+		}
+		person.addProvenance("name", nameDisplay(&p.Names[index]), p.Names[index].ValidSince, p.Names[index].LastSeen)
+	}
+
+	if p.Gender != nil {
+		switch p.Gender.Content {
+		case genderMale:
+			person.Gender = "Male"
+		case genderFemale:
+			person.Gender = "Female"
+		}
+	}
+	if p.DateOfBirth != nil {
+		person.BirthDate = p.DateOfBirth.BirthDate().String()
+	}
+
+	for _, email := range p.Emails {
+		if len(email.Address) > 0 {
+			person.Email = appendUnique(person.Email, email.Address)
+			person.addProvenance("email", email.Address, email.ValidSince, email.LastSeen)
+		}
+	}
+
+	for _, phone := range p.Phones {
+		if telephone := phoneDisplay(&phone); len(telephone) > 0 {
+			person.Telephone = appendUnique(person.Telephone, telephone)
+			person.addProvenance("telephone", telephone, phone.ValidSince, phone.LastSeen)
+		}
+	}
+
+	for _, address := range p.Addresses {
+		postal := SchemaPostalAddress{
+			Type:                "PostalAddress",
+			StreetAddress:       strings.Join(nonEmpty(address.House, address.Street, address.Apartment), " "),
+			PostOfficeBoxNumber: address.POBox,
+			AddressLocality:     address.City,
+			AddressRegion:       address.State,
+			PostalCode:          address.ZipCode,
+			AddressCountry:      address.Country,
+			AddressType:         address.Type,
+			ValidSince:          address.ValidSince,
+			LastSeen:            address.LastSeen,
+		}
+		if len(postal.StreetAddress) == 0 && len(postal.AddressLocality) == 0 && len(postal.AddressRegion) == 0 {
+			postal.Name = cmp.Or(address.Display, address.Raw) // Not parsed
+		}
+		person.Address = append(person.Address, postal)
+	}
+
+	for _, job := range p.Jobs {
+		title := cmp.Or(job.Title, job.Display)
+		if len(title) > 0 {
+			person.JobTitle = appendUnique(person.JobTitle, title)
+		}
+		if len(job.Organization) > 0 {
+			person.WorksFor = append(person.WorksFor, SchemaOrganization{
+				Type:       "Organization",
+				Name:       job.Organization,
+				JobTitle:   job.Title,
+				StartDate:  job.DateRange.Start,
+				EndDate:    job.DateRange.End,
+				ValidSince: job.ValidSince,
+				LastSeen:   job.LastSeen,
+			})
+		} else {
+			person.addProvenance("jobTitle", title, job.ValidSince, job.LastSeen)
+		}
+	}
+
+	for _, education := range p.Educations {
+		if len(education.School) > 0 {
+			person.AlumniOf = append(person.AlumniOf, SchemaOrganization{
+				Type:       "EducationalOrganization",
+				Name:       education.School,
+				Degree:     education.Degree,
+				StartDate:  education.DateRange.Start,
+				EndDate:    education.DateRange.End,
+				ValidSince: education.ValidSince,
+				LastSeen:   education.LastSeen,
+			})
+		}
+	}
+
+	for _, language := range p.Languages {
+		if tag := strings.Join(nonEmpty(language.Language, language.Region), "-"); len(tag) > 0 {
+			person.KnowsLanguage = appendUnique(person.KnowsLanguage, tag)
+		}
+	}
+
+	for _, image := range p.Images {
+		if len(image.URL) > 0 {
+			person.Image = appendUnique(person.Image, image.URL)
+			person.addProvenance("image", image.URL, image.ValidSince, image.LastSeen)
+		}
+	}
+
+	// Profiles (social and professional) are the same person, other urls are only about the person
+	for _, url := range p.URLs {
+		if len(url.URL) == 0 {
+			continue
+		}
+		property := "url"
+		if url.Category == SourceCategoryPersonalProfiles || url.Category == SourceCategoryProfessionalAndBusiness {
+			property = "sameAs"
+			person.SameAs = appendUnique(person.SameAs, url.URL)
+		} else {
+			person.URL = appendUnique(person.URL, url.URL)
+		}
+		person.addProvenance(property, url.URL, url.ValidSince, url.LastSeen)
+	}
+
+	for index := range p.Relationships {
+		person.Knows = append(person.Knows, *newSchemaRelationship(&p.Relationships[index]))
+	}
+	return person
+}
+
+// newSchemaRelationship will create the schema.org Person of the relationship (without the @context)
+func newSchemaRelationship(r *Relationship) *SchemaPerson {
+	related := &Person{
+		Addresses:       r.Addresses,
+		Educations:      r.Educations,
+		Emails:          r.Emails,
+		Ethnicities:     r.Ethnicities,
+		Images:          r.Images,
+		Jobs:            r.Jobs,
+		Languages:       r.Languages,
+		Names:           r.Names,
+		OriginCountries: r.OriginCountries,
+		Phones:          r.Phones,
+		Relationships:   r.Relationships,
+		URLs:            r.URLs,
+		UserIDs:         r.UserIDs,
+		Usernames:       r.Usernames,
+	}
+	if r.DateOfBirth != (DateOfBirth{}) {
+		related.DateOfBirth = &r.DateOfBirth
+	}
+	if r.Gender != (Gender{}) {
+		related.Gender = &r.Gender
+	}
+
+	person := newSchemaPerson(related)
+	person.RelationshipType = r.Type
+	person.Subtype = r.Subtype
+	person.ValidSince = r.ValidSince
+	person.LastSeen = r.LastSeen
+	return person
+}
+
+// addProvenance adds the dates of a text value, if it has any
+func (s *SchemaPerson) addProvenance(property, value, validSince, lastSeen string) {
+	if len(value) == 0 || (len(validSince) == 0 && len(lastSeen) == 0) {
+		return
+	}
+	s.Provenance = append(s.Provenance, SchemaProvenance{
+		Property: property, Value: value, ValidSince: validSince, LastSeen: lastSeen,
+	})
+}
+
+// nameDisplay returns the display value of the name (or the parts, or raw)
+func nameDisplay(name *Name) string {
+	return cmp.Or(name.Display, strings.Join(nonEmpty(name.Prefix, name.First, name.Middle, name.Last, name.Suffix), " "), name.Raw)
+}
+
+// phoneDisplay returns the E.164 number of the phone (see E164) with the extension, for example
+// "+19785550145 x12", or the raw number (without a country calling code)
+func phoneDisplay(phone *Phone) string {
+	e164 := phone.E164()
+	if len(e164) == 0 {
+		return cmp.Or(strings.TrimSpace(phone.Raw), phone.Display)
+	}
+	if _, _, extension := phone.parts(); extension > 0 {
+		return e164 + " x" + strconv.Itoa(extension)
+	}
+	return e164
+}
+
+// appendUnique appends the value if it is not already in the values
+func appendUnique(values []string, value string) []string {
+	if slices.Contains(values, value) {
+		return values
+	}
+	return append(values, value)
+}
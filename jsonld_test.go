@@ -0,0 +1,134 @@
+package pipl
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPerson_JSONLD will test the schema.org JSON-LD export
+func TestPerson_JSONLD(t *testing.T) {
+	t.Parallel()
+
+	person := &Person{
+		ID: "f4a7d898-6fc1-4a24-b043-43eb292a6fd5",
+		Names: []Name{
+			{First: "Clark", Last: "Kent", Display: "Clark Kent", ValidSince: "2001"},
+			{Display: "Kal El", Type: "alias"},
+		},
+		Emails: []Email{{Address: "clark@example.com", ValidSince: "2010-01-01"}, {Address: "clark@example.com"}},
+		Phones: []Phone{{CountryCode: 1, Number: 9785550145}},
+		Addresses: []Address{
+			{House: "344", Street: "Clinton St", City: "Metropolis", State: "NY", Country: "US", Type: AddressTypeHome, ValidSince: "2012"},
+			{Raw: "Smallville"},
+		},
+		Jobs: []Job{
+			{Title: "Reporter", Organization: "Daily Planet", DateRange: DateRange{Start: "2008-01-01"}},
+			{Title: "Farmer", LastSeen: "2005"},
+		},
+		Educations:  []Education{{School: "Metropolis University", Degree: "B.A. Journalism"}},
+		Images:      []Image{{URL: "https://example.com/clark.jpg"}},
+		Languages:   []Language{{Language: "en", Region: "US"}},
+		URLs:        []URL{{URL: "https://social.example.com/clark", Category: SourceCategoryPersonalProfiles}, {URL: "https://example.com/news"}},
+		DateOfBirth: &DateOfBirth{DateRange: DateRange{Start: "1986-06-18", End: "1986-06-18"}},
+		Gender:      &Gender{Content: genderMale},
+		Relationships: []Relationship{
+			{Names: []Name{{Display: "Lois Lane"}}, Type: RelationshipTypeWork, Subtype: "Colleague", ValidSince: "2009"},
+		},
+	}
+
+	data, err := person.JSONLD()
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"@context": {"@vocab": "https://schema.org/", "pipl": "https://docs.pipl.com/reference#"},
+		"@type": "Person",
+		"@id": "urn:uuid:f4a7d898-6fc1-4a24-b043-43eb292a6fd5",
+		"name": "Clark Kent",
+		"givenName": "Clark",
+		"familyName": "Kent",
+		"alternateName": ["Kal El"],
+		"gender": "Male",
+		"birthDate": "1986-06-18",
+		"email": ["clark@example.com"],
+		"telephone": ["+19785550145"],
+		"address": [
+			{"@type": "PostalAddress", "streetAddress": "344 Clinton St", "addressLocality": "Metropolis",
+			 "addressRegion": "NY", "addressCountry": "US", "pipl:type": "home", "pipl:validSince": "2012"},
+			{"@type": "PostalAddress", "name": "Smallville"}
+		],
+		"jobTitle": ["Reporter", "Farmer"],
+		"worksFor": [{"@type": "Organization", "name": "Daily Planet", "pipl:jobTitle": "Reporter", "pipl:startDate": "2008-01-01"}],
+		"alumniOf": [{"@type": "EducationalOrganization", "name": "Metropolis University", "pipl:degree": "B.A. Journalism"}],
+		"knowsLanguage": ["en-US"],
+		"image": ["https://example.com/clark.jpg"],
+		"url": ["https://example.com/news"],
+		"sameAs": ["https://social.example.com/clark"],
+		"knows": [{"@type": "Person", "name": "Lois Lane", "pipl:relationshipType": "work",
+			"pipl:relationshipSubtype": "Colleague", "pipl:validSince": "2009"}],
+		"pipl:provenance": [
+			{"pipl:property": "name", "pipl:value": "Clark Kent", "pipl:validSince": "2001"},
+			{"pipl:property": "email", "pipl:value": "clark@example.com", "pipl:validSince": "2010-01-01"},
+			{"pipl:property": "jobTitle", "pipl:value": "Farmer", "pipl:lastSeen": "2005"}
+		]
+	}`, string(data))
+
+	// Deterministic
+	again, err := person.JSONLD()
+	require.NoError(t, err)
+	assert.Equal(t, string(data), string(again))
+}
+
+// TestRelationship_JSONLD will test the JSON-LD export of a relationship
+func TestRelationship_JSONLD(t *testing.T) {
+	t.Parallel()
+
+	relationship := &Relationship{
+		Names:       []Name{{First: "Lois", Last: "Lane"}},
+		Emails:      []Email{{Address: "lois@example.com"}},
+		DateOfBirth: DateOfBirth{DateRange: DateRange{Start: "1987-01-01", End: "1987-12-31"}},
+		Type:        RelationshipTypeFriend,
+	}
+	data, err := relationship.JSONLD()
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"@context": {"@vocab": "https://schema.org/", "pipl": "https://docs.pipl.com/reference#"},
+		"@type": "Person",
+		"name": "Lois Lane",
+		"givenName": "Lois",
+		"familyName": "Lane",
+		"birthDate": "1987",
+		"email": ["lois@example.com"],
+		"pipl:relationshipType": "friend"
+	}`, string(data))
+}
+
+// TestPerson_SchemaPerson will test the JSON-LD export of a response fixture
+func TestPerson_SchemaPerson(t *testing.T) {
+	t.Parallel()
+
+	response, err := loadResponseData("response_success.json")
+	require.NoError(t, err)
+
+	schema := response.Person.SchemaPerson()
+	require.NotNil(t, schema.Context)
+	assert.Equal(t, "Person", schema.Type)
+	assert.NotEmpty(t, schema.Name)
+	assert.Len(t, schema.Knows, len(response.Person.Relationships))
+
+	data, err := json.Marshal(schema)
+	require.NoError(t, err)
+	assert.True(t, json.Valid(data))
+}
+
+// ExamplePerson_JSONLD example using JSONLD()
+func ExamplePerson_JSONLD() {
+	person := NewPerson()
+	_ = person.AddName("Clark", "", "Kent", "", "")
+	_ = person.AddEmail("clark@example.com")
+	data, _ := person.JSONLD()
+	fmt.Println(string(data))
+	// Output:{"@context":{"@vocab":"https://schema.org/","pipl":"https://docs.pipl.com/reference#"},"@type":"Person","name":"Clark Kent","givenName":"Clark","familyName":"Kent","email":["clark@example.com"]}
+}
@@ -0,0 +1,386 @@
+package pipl
+
+import (
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ValueOrigins records which input(s) each merged value came from, by field path of the
+// merged person (for example, "emails[0]" -> [0, 1]). Inputs are identified by their argument
+// position (starting at 0), so snapshots of the same person (with the same @id) stay apart.
+//
+// The paths are only valid for the merged person as returned: filtering or de-duplicating
+// it (FilterInferred(), FilterSources(), Dedupe(), etc.) changes the indexes.
+type ValueOrigins map[string][]int
+
+// mergeInput is a person being merged, with the argument position recorded as the origin of its values
+type mergeInput struct {
+	person   *Person
+	position int // Argument position (-1 to not record the origins)
+}
+
+// MergePersons will merge persons (for example, the same individual found by email,
+// phone and name searches) into a new consolidated person. The inputs are not modified,
+// and the merged person does not share any values with them.
+//
+// Every field is combined and de-duplicated by its normalized value (E.164 phone, lowercase
+// email, normalized address, etc.). Duplicates keep the most recent @last_seen and @valid_since,
+// are @current if any of them is, and fill in any empty fields. The origin of every value is
+// returned in the ValueOrigins, by argument position (nil persons are skipped, but keep their position).
+func MergePersons(persons ...*Person) (*Person, ValueOrigins) {
+	inputs := make([]mergeInput, 0, len(persons))
+	for index, person := range persons {
+		if person != nil {
+			inputs = append(inputs, mergeInput{person: person, position: index})
+		}
+	}
+	if len(inputs) == 0 {
+		return nil, nil
+	}
+
+	merged := new(Person)
+	origins := mergePersonFields(merged, inputs)
+	return merged, origins
+}
+
+// Dedupe will de-duplicate every field of the person (in place) by its normalized value,
+// combining the duplicates the same way as MergePersons
+func (p *Person) Dedupe() {
+	mergePersonFields(p, []mergeInput{{person: p, position: -1}})
+}
+
+// mergePersonFields will merge all the inputs into the person, and returns the origins of the values
+func mergePersonFields(p *Person, inputs []mergeInput) ValueOrigins {
+	origins := make(ValueOrigins)
+
+	p.Addresses = mergeField(origins, "addresses", inputs, func(p *Person) []Address { return p.Addresses }, addressKey)
+	p.Educations = mergeField(origins, "educations", inputs, func(p *Person) []Education { return p.Educations }, educationKey)
+	p.Emails = mergeField(origins, "emails", inputs, func(p *Person) []Email { return p.Emails }, emailKey)
+	p.Ethnicities = mergeField(origins, "ethnicities", inputs, func(p *Person) []Ethnicity { return p.Ethnicities }, ethnicityKey)
+	p.Images = mergeField(origins, "images", inputs, func(p *Person) []Image { return p.Images }, imageKey)
+	p.Jobs = mergeField(origins, "jobs", inputs, func(p *Person) []Job { return p.Jobs }, jobKey)
+	p.Languages = mergeField(origins, "languages", inputs, func(p *Person) []Language { return p.Languages }, languageKey)
+	p.Names = mergeField(origins, "names", inputs, func(p *Person) []Name { return p.Names }, nameKey)
+	p.OriginCountries = mergeField(origins, "origin_countries", inputs, func(p *Person) []OriginCountry { return p.OriginCountries }, originCountryKey)
+	p.Phones = mergeField(origins, "phones", inputs, func(p *Person) []Phone { return p.Phones }, phoneKey)
+	p.Relationships = mergeField(origins, "relationships", inputs, func(p *Person) []Relationship { return p.Relationships }, relationshipKey)
+	p.URLs = mergeField(origins, "urls", inputs, func(p *Person) []URL { return p.URLs }, urlKey)
+	p.UserIDs = mergeField(origins, "user_ids", inputs, func(p *Person) []UserID { return p.UserIDs }, userIDKey)
+	p.Usernames = mergeField(origins, "usernames", inputs, func(p *Person) []Username { return p.Usernames }, usernameKey)
+	p.Vehicles = mergeField(origins, "vehicles", inputs, func(p *Person) []Vehicle { return p.Vehicles }, vehicleKey)
+
+	// Single value fields (the first one wins, duplicates are combined)
+	p.DateOfBirth = mergeSingle(origins, "dob", inputs, func(p *Person) *DateOfBirth { return p.DateOfBirth }, dobKey)
+	p.Gender = mergeSingle(origins, "gender", inputs, func(p *Person) *Gender { return p.Gender }, genderKey)
+
+	// Person attributes
+	for index, input := range inputs {
+		if len(p.ID) == 0 {
+			p.ID = input.person.ID
+		}
+		if len(p.SearchPointer) == 0 {
+			p.SearchPointer = input.person.SearchPointer
+		}
+		if input.person.Match > p.Match {
+			p.Match = input.person.Match
+		}
+		if index == 0 {
+			p.Inferred = input.person.Inferred
+		} else {
+			p.Inferred = p.Inferred && input.person.Inferred
+		}
+		for key, value := range input.person.Extra {
+			if p.Extra == nil {
+				p.Extra = make(ExtraFields)
+			}
+			if _, ok := p.Extra[key]; !ok {
+				p.Extra[key] = slices.Clone(value)
+			}
+		}
+	}
+
+	if len(origins) == 0 {
+		return nil
+	}
+	return origins
+}
+
+// mergeField will combine the field values of all the inputs, de-duplicated by key
+// (values without a key are never combined), and record the origins of every value
+func mergeField[T any](origins ValueOrigins, field string, inputs []mergeInput,
+	get func(*Person) []T, key func(*T) string,
+) []T {
+	var (
+		merged      []T
+		keys        = make(map[string]int)
+		mergedFrom  [][]int
+		totalValues int
+	)
+	for _, input := range inputs {
+		totalValues += len(get(input.person))
+	}
+	if totalValues == 0 {
+		return nil
+	}
+	merged = make([]T, 0, totalValues)
+
+	for _, input := range inputs {
+		from := valueOrigins(input)
+		for _, value := range get(input.person) {
+			k := key(&value)
+			if existing, ok := keys[k]; ok && len(k) > 0 {
+				mergeValue(reflect.ValueOf(&merged[existing]).Elem(), reflect.ValueOf(value))
+				mergedFrom[existing] = appendOrigins(mergedFrom[existing], from...)
+				continue
+			}
+			if len(k) > 0 {
+				keys[k] = len(merged)
+			}
+			merged = append(merged, *deepCopy(&value))
+			mergedFrom = append(mergedFrom, appendOrigins(nil, from...))
+		}
+	}
+
+	for index, from := range mergedFrom {
+		if len(from) > 0 {
+			origins[field+"["+strconv.Itoa(index)+"]"] = from
+		}
+	}
+	return merged
+}
+
+// mergeSingle will combine a single value field (first value wins, the same value is combined)
+func mergeSingle[T any](origins ValueOrigins, field string, inputs []mergeInput,
+	get func(*Person) *T, key func(*T) string,
+) *T {
+	var (
+		from   []int
+		merged *T
+	)
+	for _, input := range inputs {
+		value := get(input.person)
+		if value == nil {
+			continue
+		}
+		if merged == nil {
+			merged = deepCopy(value)
+			from = appendOrigins(nil, valueOrigins(input)...)
+			continue
+		}
+		if key(merged) == key(value) {
+			mergeValue(reflect.ValueOf(merged).Elem(), reflect.ValueOf(*value))
+			from = appendOrigins(from, valueOrigins(input)...)
+		}
+	}
+	if len(from) > 0 {
+		origins[field] = from
+	}
+	return merged
+}
+
+// valueOrigins returns the origins of a value (the input position)
+func valueOrigins(input mergeInput) []int {
+	if input.position >= 0 {
+		return []int{input.position}
+	}
+	return nil
+}
+
+// appendOrigins appends the origins (without duplicates)
+func appendOrigins(origins []int, from ...int) []int {
+	for _, origin := range from {
+		if !slices.Contains(origins, origin) {
+			origins = append(origins, origin)
+		}
+	}
+	return origins
+}
+
+// mergeValue will combine a duplicate (src) into the value (dst): the most recent
+// @last_seen and @valid_since are kept, @current if any is current, @inferred only if
+// both are inferred, and empty fields are filled in (with a deep copy)
+func mergeValue(dst, src reflect.Value) {
+	for index := 0; index < dst.NumField(); index++ {
+		field := dst.Field(index)
+		value := src.Field(index)
+		if !field.CanSet() {
+			continue
+		}
+
+		switch dst.Type().Field(index).Name {
+		case "LastSeen", "ValidSince":
+			field.SetString(mostRecentDate(field.String(), value.String()))
+			continue
+		case "Current":
+			field.SetBool(field.Bool() || value.Bool())
+			continue
+		case "Inferred":
+			field.SetBool(field.Bool() && value.Bool())
+			continue
+		}
+
+		if field.IsZero() && !value.IsZero() {
+			copyValue(field, value)
+		}
+	}
+}
+
+// mostRecentDate returns the most recent of the two dates (a malformed date is never picked over a valid one)
+func mostRecentDate(a, b string) string {
+	if len(a) == 0 {
+		return b
+	} else if len(b) == 0 {
+		return a
+	}
+	dateA, errA := ParseDate(a)
+	dateB, errB := ParseDate(b)
+	if errB != nil {
+		return a
+	} else if errA != nil || dateB.Time.After(dateA.Time) {
+		return b
+	}
+	return a
+}
+
+// normalizeText will lowercase the text, remove punctuation and collapse the whitespace
+func normalizeText(text string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return unicode.IsSpace(r) || (unicode.IsPunct(r) && r != '@' && r != '-' && r != '_')
+	}), " ")
+}
+
+// joinKey joins the normalized parts into a key (empty if all the parts are empty)
+func joinKey(parts ...string) string {
+	empty := true
+	for index, part := range parts {
+		parts[index] = normalizeText(part)
+		empty = empty && len(parts[index]) == 0
+	}
+	if empty {
+		return ""
+	}
+	return strings.Join(parts, "|")
+}
+
+// emailKey is the normalized email (lowercase address, or the MD5 if there is no address)
+func emailKey(v *Email) string {
+	if address := strings.ToLower(strings.TrimSpace(v.Address)); len(address) > 0 {
+		return address
+	}
+	return strings.ToLower(v.AddressMD5)
+}
+
+// addressKey is the normalized address (from the parts, or the raw/display address)
+func addressKey(v *Address) string {
+	key := normalizeText(strings.Join([]string{
+		v.House, v.Street, v.Apartment, v.POBox, v.City, v.State, v.ZipCode, v.Country,
+	}, " "))
+	if len(v.Street) == 0 && len(v.POBox) == 0 {
+		if raw := normalizeText(v.Raw); len(raw) > 0 {
+			return raw
+		}
+		if display := normalizeText(v.Display); len(display) > 0 {
+			return display
+		}
+	}
+	return key
+}
+
+// nameKey is the normalized name
+func nameKey(v *Name) string {
+	if key := normalizeText(strings.Join([]string{v.Prefix, v.First, v.Middle, v.Last, v.Suffix}, " ")); len(key) > 0 {
+		return key
+	}
+	if raw := normalizeText(v.Raw); len(raw) > 0 {
+		return raw
+	}
+	return normalizeText(v.Display)
+}
+
+// imageKey is the image URL (or the thumbnail token)
+func imageKey(v *Image) string {
+	if len(v.URL) > 0 {
+		return strings.TrimSpace(v.URL)
+	}
+	return v.ThumbnailToken
+}
+
+// urlKey is the normalized URL
+func urlKey(v *URL) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(v.URL)), "/")
+}
+
+// jobKey is the normalized title, organization and industry
+func jobKey(v *Job) string {
+	if key := joinKey(v.Title, v.Organization, v.Industry); len(key) > 0 {
+		return key
+	}
+	return normalizeText(v.Display)
+}
+
+// educationKey is the normalized degree and school
+func educationKey(v *Education) string {
+	if key := joinKey(v.Degree, v.School); len(key) > 0 {
+		return key
+	}
+	return normalizeText(v.Display)
+}
+
+// dobKey is the normalized date range of the date of birth
+func dobKey(v *DateOfBirth) string {
+	return joinKey(v.DateRange.Start, v.DateRange.End)
+}
+
+// ethnicityKey is the normalized ethnicity
+func ethnicityKey(v *Ethnicity) string {
+	return normalizeText(v.Content)
+}
+
+// genderKey is the normalized gender
+func genderKey(v *Gender) string {
+	return normalizeText(v.Content)
+}
+
+// languageKey is the normalized language and region
+func languageKey(v *Language) string {
+	return joinKey(v.Language, v.Region)
+}
+
+// originCountryKey is the normalized country
+func originCountryKey(v *OriginCountry) string {
+	return normalizeText(v.Country)
+}
+
+// userIDKey is the normalized user ID
+func userIDKey(v *UserID) string {
+	return normalizeText(v.Content)
+}
+
+// usernameKey is the normalized username
+func usernameKey(v *Username) string {
+	return normalizeText(v.Content)
+}
+
+// relationshipKey is the search pointer, or the type and name of the related person
+func relationshipKey(v *Relationship) string {
+	if len(v.SearchPointer) > 0 {
+		return "pointer:" + v.SearchPointer
+	} else if len(v.Names) == 0 {
+		return ""
+	}
+	return string(v.Type) + ":" + nameKey(&v.Names[0])
+}
+
+// vehicleKey is the VIN, or the make, model and year
+func vehicleKey(v *Vehicle) string {
+	if vin := strings.ToUpper(strings.TrimSpace(v.VIN)); len(vin) > 0 {
+		return vin
+	}
+	if key := normalizeText(v.Make + " " + v.Model); len(key) > 0 {
+		return key + " " + strconv.Itoa(v.Year)
+	}
+	return ""
+}
@@ -0,0 +1,193 @@
+package pipl
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestMergePersons returns the same person found by an email search and a phone search
+func newTestMergePersons() (*Person, *Person) {
+	byEmail := &Person{
+		ID: "email-person",
+		Addresses: []Address{
+			{House: "1000", Street: "Broadway", City: "Metropolis", State: "KS", Country: "US", ValidSince: "2005-02-12"},
+		},
+		Emails: []Email{
+			{Address: "Clark.Kent@Example.com", ValidSince: "2010-01-01", LastSeen: "2018-05-01"},
+		},
+		Names:  []Name{{First: "Clark", Last: "Kent", Display: "Clark Kent"}},
+		Phones: []Phone{{CountryCode: 1, Number: 9785550145, Display: "978-555-0145"}},
+		Gender: &Gender{Content: "male"},
+		Match:  0.8,
+	}
+	byPhone := &Person{
+		ID: "phone-person",
+		Addresses: []Address{
+			{House: "1000", Street: "broadway.", City: "metropolis", State: "ks", Country: "us", Current: true},
+		},
+		Emails: []Email{
+			{Address: "clark.kent@example.com", Type: EmailTypePersonal, ValidSince: "2012-03", LastSeen: "2019-01-01", Current: true},
+			{Address: "superman@example.com"},
+		},
+		Names:  []Name{{First: "clark", Last: "kent"}},
+		Phones: []Phone{{DisplayInternational: "+1 978-555-0145", Type: PhoneTypeMobile}},
+		Gender: &Gender{Content: "Male", Current: true},
+		Match:  0.9,
+	}
+	return byEmail, byPhone
+}
+
+// TestMergePersons will test merging two persons
+func TestMergePersons(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil persons", func(t *testing.T) {
+		merged, origins := MergePersons(nil, nil)
+		assert.Nil(t, merged)
+		assert.Nil(t, origins)
+
+		a, _ := newTestMergePersons()
+		merged, origins = MergePersons(a, nil)
+		require.NotNil(t, merged)
+		assert.NotSame(t, a, merged)
+		assert.Equal(t, a.Emails, merged.Emails)
+		assert.Equal(t, []int{0}, origins["emails[0]"])
+	})
+
+	t.Run("merge and de-duplicate", func(t *testing.T) {
+		a, b := newTestMergePersons()
+		merged, origins := MergePersons(a, b)
+		require.NotNil(t, merged)
+
+		// Emails (lowercase)
+		require.Len(t, merged.Emails, 2)
+		assert.Equal(t, "Clark.Kent@Example.com", merged.Emails[0].Address)
+		assert.Equal(t, EmailTypePersonal, merged.Emails[0].Type)
+		assert.Equal(t, "2012-03", merged.Emails[0].ValidSince)
+		assert.Equal(t, "2019-01-01", merged.Emails[0].LastSeen)
+		assert.True(t, merged.Emails[0].Current)
+		assert.Equal(t, []int{0, 1}, origins["emails[0]"])
+		assert.Equal(t, []int{1}, origins["emails[1]"])
+
+		// Phones (E.164)
+		require.Len(t, merged.Phones, 1)
+		assert.Equal(t, PhoneTypeMobile, merged.Phones[0].Type)
+		assert.Equal(t, "+1 978-555-0145", merged.Phones[0].DisplayInternational)
+
+		// Addresses (normalized) and names
+		require.Len(t, merged.Addresses, 1)
+		assert.True(t, merged.Addresses[0].Current)
+		assert.Equal(t, "2005-02-12", merged.Addresses[0].ValidSince)
+		require.Len(t, merged.Names, 1)
+		assert.Equal(t, "Clark Kent", merged.Names[0].Display)
+
+		// Single value fields and person attributes
+		require.NotNil(t, merged.Gender)
+		assert.True(t, merged.Gender.Current)
+		assert.Equal(t, []int{0, 1}, origins["gender"])
+		assert.Equal(t, GUID("email-person"), merged.ID)
+		assert.InDelta(t, 0.9, merged.Match, 0.001)
+
+		// Inputs are not modified
+		assert.Len(t, b.Emails, 2)
+		assert.False(t, a.Emails[0].Current)
+	})
+
+	t.Run("merged values are copies of the inputs", func(t *testing.T) {
+		a, b := newTestMergePersons()
+		a.Relationships = []Relationship{{Type: RelationshipTypeFamily, Names: []Name{{First: "Martha", Last: "Kent"}}}}
+		b.Relationships = []Relationship{{
+			Type: RelationshipTypeFamily, Names: []Name{{First: "martha", Last: "kent"}},
+			Emails: []Email{{Address: "martha@example.com"}},
+		}}
+
+		merged, _ := MergePersons(a, b)
+		require.Len(t, merged.Relationships, 1)
+		require.Len(t, merged.Relationships[0].Emails, 1)
+		merged.Relationships[0].Names[0].First = "Ma"
+		merged.Relationships[0].Emails[0].Address = "ma@example.com"
+		merged.Gender.Content = "female"
+
+		assert.Equal(t, "Martha", a.Relationships[0].Names[0].First)
+		assert.Equal(t, "martha@example.com", b.Relationships[0].Emails[0].Address)
+		assert.Equal(t, "male", a.Gender.Content)
+	})
+
+	t.Run("more than two persons", func(t *testing.T) {
+		a, b := newTestMergePersons()
+		c := &Person{Emails: []Email{{Address: "SUPERMAN@example.com"}}}
+
+		merged, origins := MergePersons(a, b, c)
+		require.Len(t, merged.Emails, 2)
+		assert.Equal(t, []int{1, 2}, origins["emails[1]"])
+	})
+
+	t.Run("snapshots with the same id are told apart", func(t *testing.T) {
+		older := &Person{ID: "clark", Emails: []Email{{Address: "clark@example.com"}}}
+		newer := &Person{ID: "clark", Emails: []Email{{Address: "clark@example.com"}, {Address: "superman@example.com"}}}
+
+		merged, origins := MergePersons(older, nil, newer)
+		require.Len(t, merged.Emails, 2)
+		assert.Equal(t, []int{0, 2}, origins["emails[0]"])
+		assert.Equal(t, []int{2}, origins["emails[1]"])
+	})
+
+	t.Run("most recent date wins, malformed dates are ignored", func(t *testing.T) {
+		assert.Equal(t, "2019", mostRecentDate("2018-12-31", "2019"))
+		assert.Equal(t, "2018-12-31", mostRecentDate("2018-12-31", "bad"))
+		assert.Equal(t, "2018", mostRecentDate("bad", "2018"))
+		assert.Equal(t, "2018", mostRecentDate("", "2018"))
+	})
+}
+
+// TestPerson_Dedupe will test de-duplicating a person in place
+func TestPerson_Dedupe(t *testing.T) {
+	t.Parallel()
+
+	person := &Person{
+		Emails: []Email{
+			{Address: "clark@example.com", LastSeen: "2015"},
+			{Address: " CLARK@example.com ", LastSeen: "2016-01"},
+		},
+		Phones: []Phone{
+			{CountryCode: 1, Number: 9785550145},
+			{CountryCode: 1, Number: 9785550145, Extension: 12},
+			{Raw: "+1 (978) 555-0145"},
+		},
+		URLs: []URL{
+			{URL: "https://example.com/clark/"},
+			{URL: "https://EXAMPLE.com/clark", Category: SourceCategoryPersonalProfiles},
+		},
+		Jobs: []Job{{}, {}}, // No key, never combined
+	}
+	person.Dedupe()
+
+	require.Len(t, person.Emails, 1)
+	assert.Equal(t, "2016-01", person.Emails[0].LastSeen)
+	require.Len(t, person.Phones, 2)
+	assert.Equal(t, 12, person.Phones[1].Extension)
+	require.Len(t, person.URLs, 1)
+	assert.Equal(t, SourceCategoryPersonalProfiles, person.URLs[0].Category)
+	assert.Len(t, person.Jobs, 2)
+}
+
+// ExampleMergePersons example using MergePersons()
+func ExampleMergePersons() {
+	byEmail := &Person{Emails: []Email{{Address: "clark@example.com"}}}
+	byPhone := &Person{Emails: []Email{{Address: "Clark@Example.com"}, {Address: "superman@example.com"}}}
+
+	merged, origins := MergePersons(byEmail, byPhone)
+	fmt.Println(len(merged.Emails), origins["emails[0]"])
+	// Output:2 [0 1]
+}
+
+// BenchmarkMergePersons benchmarks the MergePersons method
+func BenchmarkMergePersons(b *testing.B) {
+	byEmail, byPhone := newTestMergePersons()
+	for i := 0; i < b.N; i++ {
+		_, _ = MergePersons(byEmail, byPhone)
+	}
+}
@@ -5,6 +5,9 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
 )
 
 // validResponse will return valid response(s)
@@ -83,3 +86,117 @@ func (v *errorMissingAPIKeyResponse) Do(_ *http.Request) (*http.Response, error)
 	resp.Body = io.NopCloser(bytes.NewReader([]byte(`{"@http_status_code": 403,"error": "Please provide an API key"}`)))
 	return resp, nil
 }
+
+// rawResponse will return the raw body for any request
+type rawResponse struct {
+	body string
+}
+
+// Do will do the HTTP request
+func (r *rawResponse) Do(_ *http.Request) (*http.Response, error) {
+	resp := new(http.Response)
+	resp.StatusCode = http.StatusOK
+	resp.Body = io.NopCloser(strings.NewReader(r.body))
+	return resp, nil
+}
+
+const (
+	// testFailDomain is the email domain (or search pointer prefix) that the searchResponse mock fails on
+	testFailDomain = "fail.example.com"
+
+	// testNotFoundDomain is the email domain that the searchResponse mock does not find a person for
+	testNotFoundDomain = "notfound.example.com"
+)
+
+// searchResponse will return a valid response for any search or search pointer,
+// unless the request contains testFailDomain (then an API error is returned)
+type searchResponse struct {
+	calls      atomic.Int32
+	delay      time.Duration
+	lastForm   atomic.Value // url.Values of the last request
+	searchFile string       // response file for searches (default: response_success.json)
+}
+
+// Do will do the HTTP request
+func (s *searchResponse) Do(req *http.Request) (*http.Response, error) {
+	s.calls.Add(1)
+
+	// Parse the form data
+	if err := req.ParseForm(); err != nil {
+		return nil, err
+	}
+	s.lastForm.Store(req.Form)
+
+	// Simulate a slow API
+	if s.delay > 0 {
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(s.delay):
+		}
+	}
+
+	resp := new(http.Response)
+	resp.StatusCode = http.StatusOK
+
+	// Failed search
+	if strings.Contains(req.Form.Get(fieldPerson), testFailDomain) ||
+		strings.HasPrefix(req.Form.Get(fieldSearchPointer), testFailDomain) {
+		resp.Body = io.NopCloser(bytes.NewReader([]byte(`{"@http_status_code": 400,"error": "search failed"}`)))
+		return resp, nil
+	}
+
+	// Return the success response
+	filename := "response_success.json"
+	pointer := req.Form.Get(fieldSearchPointer)
+	if len(pointer) == 0 && len(s.searchFile) > 0 {
+		filename = s.searchFile
+	} else if strings.Contains(req.Form.Get(fieldPerson), testNotFoundDomain) {
+		filename = "response_not_found.json"
+	}
+	response, err := loadResponseData(filename)
+	if err != nil {
+		return nil, err
+	}
+	if len(pointer) > 0 {
+		response.Person.SearchPointer = pointer
+	}
+	var b []byte
+	if b, err = json.Marshal(response); err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewBuffer(b))
+	return resp, nil
+}
+
+// personsResponse will return the person for each search pointer (or an API error if the pointer is unknown)
+type personsResponse struct {
+	calls   atomic.Int32
+	persons map[string]Person
+}
+
+// Do will do the HTTP request
+func (p *personsResponse) Do(req *http.Request) (*http.Response, error) {
+	p.calls.Add(1)
+
+	// Parse the form data
+	if err := req.ParseForm(); err != nil {
+		return nil, err
+	}
+
+	resp := new(http.Response)
+	resp.StatusCode = http.StatusOK
+
+	person, ok := p.persons[req.Form.Get(fieldSearchPointer)]
+	if !ok {
+		resp.Body = io.NopCloser(bytes.NewReader([]byte(`{"@http_status_code": 400,"error": "unknown search pointer"}`)))
+		return resp, nil
+	}
+
+	b, err := json.Marshal(&Response{HTTPStatusCode: http.StatusOK, PersonsCount: 1, Person: person})
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewBuffer(b))
+	return resp, nil
+}
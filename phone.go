@@ -0,0 +1,441 @@
+package pipl
+
+import (
+	"cmp"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Phone number limits (ITU-T Recommendation E.164)
+const (
+	phoneCodeNANP   = 1  // North American Numbering Plan (US, CA and the Caribbean)
+	phoneMaxDigits  = 15 // Maximum digits of a phone number, including the country calling code
+	phoneMinDigits  = 4  // Minimum digits of a phone number
+	phoneMaxCodeLen = 3  // Maximum digits of a country calling code
+)
+
+// phoneExtensionPattern matches an extension at the end of a phone number, for example "ext. 12"
+var phoneExtensionPattern = regexp.MustCompile(`(?i)\s*[,;]?\s*(?:ext\.?|extension|x|#)\s*(\d{1,7})\s*$`) //nolint:gochecknoglobals // Compiled once
+
+// phonePlan is the numbering plan of a country calling code
+type phonePlan struct {
+	minLength int    // Minimum digits of the national number
+	maxLength int    // Maximum digits of the national number
+	trunk     string // Trunk (national) prefix, for example "0"
+	groups    []int  // Digit groups of the display format (without groups, there are no display formats)
+}
+
+// phonePlans is the numbering plan of every country calling code
+//
+// Source: https://www.itu.int/pub/T-SP-E.164D
+var phonePlans = map[int]phonePlan{ //nolint:gochecknoglobals // Country calling code metadata
+	1:   {10, 10, "1", []int{3, 3, 4}},
+	7:   {10, 10, "8", []int{3, 3, 2, 2}},
+	20:  {8, 10, "0", nil},
+	27:  {9, 9, "0", nil},
+	30:  {10, 10, "", nil},
+	31:  {9, 9, "0", nil},
+	32:  {8, 9, "0", nil},
+	33:  {9, 9, "0", []int{1, 2, 2, 2, 2}},
+	34:  {9, 9, "", []int{3, 3, 3}},
+	36:  {8, 9, "06", nil},
+	39:  {6, 11, "", nil},
+	40:  {9, 9, "0", nil},
+	41:  {9, 9, "0", nil},
+	43:  {4, 13, "0", nil},
+	44:  {7, 10, "0", nil},
+	45:  {8, 8, "", []int{2, 2, 2, 2}},
+	46:  {7, 13, "0", nil},
+	47:  {5, 8, "", nil},
+	48:  {9, 9, "", []int{3, 3, 3}},
+	49:  {5, 13, "0", nil},
+	51:  {8, 9, "0", nil},
+	52:  {10, 10, "", nil},
+	53:  {6, 8, "0", nil},
+	54:  {10, 11, "0", nil},
+	55:  {10, 11, "0", nil},
+	56:  {9, 9, "", nil},
+	57:  {8, 10, "", nil},
+	58:  {10, 10, "0", nil},
+	60:  {7, 10, "0", nil},
+	61:  {5, 10, "0", nil},
+	62:  {7, 12, "0", nil},
+	63:  {8, 10, "0", nil},
+	64:  {8, 10, "0", nil},
+	65:  {8, 10, "", nil},
+	66:  {8, 9, "0", nil},
+	81:  {8, 10, "0", nil},
+	82:  {7, 11, "0", nil},
+	84:  {9, 10, "0", nil},
+	86:  {7, 12, "0", nil},
+	90:  {10, 10, "0", nil},
+	91:  {10, 12, "0", nil},
+	92:  {9, 11, "0", nil},
+	93:  {9, 9, "0", nil},
+	94:  {9, 9, "0", nil},
+	95:  {7, 10, "0", nil},
+	98:  {6, 10, "0", nil},
+	211: {9, 9, "0", nil},
+	212: {9, 9, "0", nil},
+	213: {8, 9, "0", nil},
+	216: {8, 8, "", nil},
+	218: {9, 9, "0", nil},
+	220: {7, 7, "", nil},
+	221: {9, 9, "", nil},
+	222: {8, 8, "", nil},
+	223: {8, 8, "", nil},
+	224: {8, 9, "", nil},
+	225: {8, 10, "", nil},
+	226: {8, 8, "", nil},
+	227: {8, 8, "", nil},
+	228: {8, 8, "", nil},
+	229: {8, 10, "", nil},
+	230: {7, 8, "", nil},
+	231: {7, 9, "0", nil},
+	232: {8, 8, "0", nil},
+	233: {9, 9, "0", nil},
+	234: {7, 10, "0", nil},
+	235: {8, 8, "", nil},
+	236: {8, 8, "", nil},
+	237: {8, 9, "", nil},
+	238: {7, 7, "", nil},
+	239: {7, 7, "", nil},
+	240: {9, 9, "", nil},
+	241: {7, 8, "", nil},
+	242: {9, 9, "", nil},
+	243: {7, 9, "0", nil},
+	244: {9, 9, "", nil},
+	245: {7, 9, "", nil},
+	246: {7, 7, "", nil},
+	247: {4, 5, "", nil},
+	248: {7, 7, "", nil},
+	249: {9, 9, "0", nil},
+	250: {9, 9, "", nil},
+	251: {9, 9, "0", nil},
+	252: {7, 9, "0", nil},
+	253: {8, 8, "", nil},
+	254: {7, 10, "0", nil},
+	255: {9, 9, "0", nil},
+	256: {9, 9, "0", nil},
+	257: {8, 8, "", nil},
+	258: {8, 9, "", nil},
+	260: {9, 9, "0", nil},
+	261: {9, 9, "0", nil},
+	262: {9, 9, "0", nil},
+	263: {5, 10, "0", nil},
+	264: {7, 10, "0", nil},
+	265: {7, 9, "0", nil},
+	266: {8, 8, "", nil},
+	267: {7, 8, "", nil},
+	268: {8, 8, "", nil},
+	269: {7, 7, "", nil},
+	290: {4, 5, "", nil},
+	291: {7, 7, "0", nil},
+	297: {7, 7, "", nil},
+	298: {6, 6, "", nil},
+	299: {6, 6, "", nil},
+	350: {8, 8, "", nil},
+	351: {9, 9, "", nil},
+	352: {4, 11, "", nil},
+	353: {5, 10, "0", nil},
+	354: {7, 9, "", nil},
+	355: {6, 9, "0", nil},
+	356: {8, 8, "", nil},
+	357: {8, 8, "", nil},
+	358: {5, 12, "0", nil},
+	359: {6, 9, "0", nil},
+	370: {8, 8, "8", nil},
+	371: {8, 8, "", nil},
+	372: {7, 8, "", nil},
+	373: {8, 8, "0", nil},
+	374: {8, 8, "0", nil},
+	375: {9, 10, "8", nil},
+	376: {6, 9, "", nil},
+	377: {8, 9, "0", nil},
+	378: {6, 10, "", nil},
+	380: {9, 9, "0", nil},
+	381: {6, 12, "0", nil},
+	382: {8, 8, "0", nil},
+	383: {8, 9, "0", nil},
+	385: {6, 9, "0", nil},
+	386: {8, 8, "0", nil},
+	387: {8, 9, "0", nil},
+	389: {8, 8, "0", nil},
+	420: {9, 9, "", []int{3, 3, 3}},
+	421: {9, 9, "0", nil},
+	423: {7, 9, "", nil},
+	500: {5, 5, "", nil},
+	501: {7, 7, "", nil},
+	502: {8, 8, "", nil},
+	503: {7, 11, "", nil},
+	504: {8, 8, "", nil},
+	505: {8, 8, "", nil},
+	506: {8, 10, "", nil},
+	507: {7, 8, "", nil},
+	508: {6, 6, "", nil},
+	509: {8, 8, "", nil},
+	590: {9, 9, "0", nil},
+	591: {8, 8, "0", nil},
+	592: {7, 7, "", nil},
+	593: {8, 9, "0", nil},
+	594: {9, 9, "0", nil},
+	595: {6, 9, "0", nil},
+	596: {9, 9, "0", nil},
+	597: {6, 7, "", nil},
+	598: {8, 8, "0", nil},
+	599: {7, 8, "", nil},
+	670: {7, 8, "", nil},
+	672: {6, 6, "", nil},
+	673: {7, 7, "", nil},
+	674: {7, 7, "", nil},
+	675: {7, 8, "", nil},
+	676: {5, 7, "", nil},
+	677: {5, 7, "", nil},
+	678: {5, 7, "", nil},
+	679: {7, 7, "", nil},
+	680: {7, 7, "", nil},
+	681: {6, 6, "", nil},
+	682: {5, 5, "", nil},
+	683: {4, 7, "", nil},
+	685: {5, 7, "", nil},
+	686: {5, 8, "0", nil},
+	687: {6, 6, "", nil},
+	688: {5, 7, "", nil},
+	689: {6, 8, "", nil},
+	690: {4, 7, "", nil},
+	691: {7, 7, "", nil},
+	692: {7, 7, "", nil},
+	800: {8, 8, "", nil},
+	808: {8, 8, "", nil},
+	850: {6, 10, "0", nil},
+	852: {8, 9, "", []int{4, 4}},
+	853: {8, 8, "", []int{4, 4}},
+	855: {8, 9, "0", nil},
+	856: {8, 10, "0", nil},
+	870: {9, 9, "", nil},
+	878: {12, 12, "", nil},
+	880: {6, 10, "0", nil},
+	881: {9, 10, "", nil},
+	882: {7, 12, "", nil},
+	883: {9, 12, "", nil},
+	886: {8, 9, "0", nil},
+	888: {11, 11, "", nil},
+	960: {7, 7, "", nil},
+	961: {7, 8, "0", nil},
+	962: {8, 9, "0", nil},
+	963: {8, 9, "0", nil},
+	964: {8, 10, "0", nil},
+	965: {7, 8, "", nil},
+	966: {9, 9, "0", nil},
+	967: {7, 9, "0", nil},
+	968: {8, 8, "", nil},
+	970: {8, 9, "0", nil},
+	971: {8, 9, "0", nil},
+	972: {8, 9, "0", nil},
+	973: {8, 8, "", nil},
+	974: {7, 8, "", nil},
+	975: {7, 8, "", nil},
+	976: {8, 8, "0", nil},
+	977: {8, 10, "0", nil},
+	979: {9, 9, "", nil},
+	992: {9, 9, "", nil},
+	993: {8, 8, "8", nil},
+	994: {9, 9, "0", nil},
+	995: {9, 9, "0", nil},
+	996: {9, 9, "0", nil},
+	998: {9, 9, "", nil},
+}
+
+// ParsePhoneNumber will parse a formatted or international phone number (for example
+// "+1 (978) 555-0145 ext. 12" or "0044 20 7123 4567") into a phone with the country calling code,
+// number, extension, display formats and the E.164 number (in Raw).
+//
+// National numbers (without "+" or "00") use the default country calling code, and the
+// trunk prefix is removed (for example, the "0" of "020 7123 4567"). The length of the number
+// is validated for the numbering plan of the country calling code.
+func ParsePhoneNumber(phoneNumber string, defaultCountryCode int) (*Phone, error) {
+	digits, extension, international, err := splitPhoneNumber(phoneNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	countryCode := defaultCountryCode
+	if international {
+		if countryCode, digits = splitCountryCode(digits); countryCode == 0 {
+			return nil, ErrUnknownCountryCode
+		}
+	} else if countryCode == 0 {
+		return nil, ErrMissingCountryCode
+	}
+
+	plan, ok := phonePlans[countryCode]
+	if !ok {
+		return nil, ErrUnknownCountryCode
+	}
+	if !international && len(plan.trunk) > 0 && strings.HasPrefix(digits, plan.trunk) &&
+		len(digits)-len(plan.trunk) >= plan.minLength {
+		digits = digits[len(plan.trunk):]
+	}
+
+	phone, err := newPhone(countryCode, digits, extension)
+	if err != nil {
+		return nil, err
+	}
+	phone.Raw = "+" + strconv.Itoa(countryCode) + digits
+	return phone, nil
+}
+
+// E164 returns the phone number in the E.164 format, for example "+19785550145" (empty without
+// a country calling code and number). The digits are parsed from the Raw, DisplayInternational
+// or Display (so a leading zero of the number is kept, for example "+390612345678" for an Italian
+// landline), the Number is only used if none of them can be parsed.
+func (p *Phone) E164() string {
+	countryCode, digits, _ := p.parts()
+	if countryCode == 0 || len(digits) == 0 {
+		return ""
+	}
+	return "+" + strconv.Itoa(countryCode) + digits
+}
+
+// phoneKey returns the normalized number of the phone: the E.164 number (see E164), or the digits
+// of a national number without a country calling code, with the extension (for example
+// "+19785550145x12"), or empty if the phone has no number. Phones are compared by this key
+// (MergePersons, Graph, QueryHash and ParseIdentity).
+func phoneKey(p *Phone) string {
+	key := p.E164()
+	countryCode, digits, extension := p.parts()
+	if countryCode == 0 {
+		key = digits
+	}
+	if len(key) > 0 && extension > 0 {
+		key += "x" + strconv.Itoa(extension)
+	}
+	return key
+}
+
+// parts returns the country calling code (zero if unknown), the national number and the extension
+// of the phone, parsed with ParsePhoneNumber() from the formatted values, or from the Number
+func (p *Phone) parts() (countryCode int, digits string, extension int) {
+	for _, value := range []string{p.Raw, p.DisplayInternational, p.Display} {
+		phone, err := ParsePhoneNumber(value, p.CountryCode)
+		if err != nil || (p.CountryCode > 0 && phone.CountryCode != p.CountryCode) {
+			continue
+		}
+		digits = strings.TrimPrefix(phone.Raw, "+"+strconv.Itoa(phone.CountryCode))
+		return phone.CountryCode, digits, cmp.Or(p.Extension, phone.Extension)
+	}
+	if p.Number > 0 {
+		return p.CountryCode, strconv.FormatInt(p.Number, 10), p.Extension
+	}
+
+	// National number without a country calling code
+	for _, value := range []string{p.Raw, p.Display} {
+		if digits, extension, international, err := splitPhoneNumber(value); err == nil && !international {
+			return 0, digits, cmp.Or(p.Extension, extension)
+		}
+	}
+	return 0, "", p.Extension
+}
+
+// newPhone returns a phone after validating the national number for the country calling code
+func newPhone(countryCode int, digits string, extension int) (*Phone, error) {
+	plan, ok := phonePlans[countryCode]
+	if !ok {
+		return nil, ErrUnknownCountryCode
+	}
+	if len(digits) < plan.minLength || len(digits) > plan.maxLength ||
+		len(digits)+len(strconv.Itoa(countryCode)) > phoneMaxDigits {
+		return nil, ErrInvalidPhoneLength
+	}
+	number, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil || number <= 0 {
+		return nil, ErrInvalidPhoneNumber
+	}
+
+	// The trunk prefix of the NANP is not written, for example "978-555-0145"
+	prefix, separator := plan.trunk, " "
+	if countryCode == phoneCodeNANP {
+		prefix, separator = "", "-"
+	}
+	phone := &Phone{CountryCode: countryCode, Extension: extension, Number: number}
+	grouped, ok := groupPhoneDigits(digits, plan.groups, separator)
+	if !ok {
+		return phone, nil // Unknown display format
+	}
+	phone.Display = prefix + grouped
+	phone.DisplayInternational = "+" + strconv.Itoa(countryCode) + " " + grouped
+	if extension > 0 {
+		phone.Display += " x" + strconv.Itoa(extension)
+		phone.DisplayInternational += " x" + strconv.Itoa(extension)
+	}
+	return phone, nil
+}
+
+// splitPhoneNumber returns the digits and extension of a phone number, and whether it
+// starts with an international prefix ("+" or "00")
+func splitPhoneNumber(phoneNumber string) (digits string, extension int, international bool, err error) {
+	value := strings.TrimSpace(phoneNumber)
+	if groups := phoneExtensionPattern.FindStringSubmatch(value); groups != nil {
+		extension, _ = strconv.Atoi(groups[1])
+		value = strings.TrimSpace(value[:len(value)-len(groups[0])])
+	}
+
+	international = strings.HasPrefix(value, "+")
+	if international {
+		value = strings.ReplaceAll(value, "(0)", "") // For example "+44 (0)20 7123 4567"
+	}
+
+	var builder strings.Builder
+	for index, r := range value {
+		switch {
+		case r >= '0' && r <= '9':
+			builder.WriteRune(r)
+		case r == '+' && index == 0:
+		case r == ' ' || r == '.' || r == '-' || r == '(' || r == ')' || r == '/':
+		default:
+			return "", 0, false, ErrInvalidPhoneNumber
+		}
+	}
+
+	digits = builder.String()
+	if !international && strings.HasPrefix(digits, "00") {
+		international = true
+		digits = digits[2:]
+	}
+	if len(digits) < phoneMinDigits || len(digits) > phoneMaxDigits {
+		return "", 0, false, ErrInvalidPhoneNumber
+	}
+	return digits, extension, international, nil
+}
+
+// splitCountryCode returns the country calling code and the national number
+// (country calling codes are prefix-free), or zero if the country calling code is unknown
+func splitCountryCode(digits string) (int, string) {
+	for length := 1; length <= phoneMaxCodeLen && length < len(digits); length++ {
+		code, _ := strconv.Atoi(digits[:length])
+		if _, ok := phonePlans[code]; ok {
+			return code, digits[length:]
+		}
+	}
+	return 0, digits
+}
+
+// groupPhoneDigits returns the digits in groups, for example "978-555-0145", or false if the
+// groups are unknown (or do not match the length of the number)
+func groupPhoneDigits(digits string, groups []int, separator string) (string, bool) {
+	var total int
+	for _, size := range groups {
+		total += size
+	}
+	if len(groups) == 0 || total != len(digits) {
+		return "", false
+	}
+
+	parts := make([]string, 0, len(groups))
+	for _, size := range groups {
+		parts = append(parts, digits[:size])
+		digits = digits[size:]
+	}
+	return strings.Join(parts, separator), true
+}
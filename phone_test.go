@@ -0,0 +1,177 @@
+package pipl
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParsePhoneNumber will test parsing phone numbers
+func TestParsePhoneNumber(t *testing.T) {
+	t.Parallel()
+
+	t.Run("international formats", func(t *testing.T) {
+		tests := []struct {
+			input                string
+			countryCode          int
+			number               int64
+			extension            int
+			e164                 string
+			display              string
+			displayInternational string
+		}{
+			{"+1 (978) 555-0145", 1, 9785550145, 0, "+19785550145", "978-555-0145", "+1 978-555-0145"},
+			{"+1-978-555-0145 ext. 12", 1, 9785550145, 12, "+19785550145", "978-555-0145 x12", "+1 978-555-0145 x12"},
+			{"0044 20 7123 4567", 44, 2071234567, 0, "+442071234567", "", ""}, // No known display format
+			{"+44 (0)20 7123 4567", 44, 2071234567, 0, "+442071234567", "", ""},
+			{"+33 1 23 45 67 89", 33, 123456789, 0, "+33123456789", "01 23 45 67 89", "+33 1 23 45 67 89"},
+			{"+7 495 123-45-67", 7, 4951234567, 0, "+74951234567", "8495 123 45 67", "+7 495 123 45 67"},
+			{"+353 1 234 5678 x9", 353, 12345678, 9, "+35312345678", "", ""},
+			{"+48 512 345 678", 48, 512345678, 0, "+48512345678", "512 345 678", "+48 512 345 678"},
+		}
+		for _, test := range tests {
+			t.Run(test.input, func(t *testing.T) {
+				phone, err := ParsePhoneNumber(test.input, 0)
+				require.NoError(t, err)
+				assert.Equal(t, test.countryCode, phone.CountryCode)
+				assert.Equal(t, test.number, phone.Number)
+				assert.Equal(t, test.extension, phone.Extension)
+				assert.Equal(t, test.e164, phone.Raw)
+				assert.Equal(t, test.e164, phone.E164())
+				assert.Equal(t, test.display, phone.Display)
+				assert.Equal(t, test.displayInternational, phone.DisplayInternational)
+			})
+		}
+	})
+
+	t.Run("national with default country code", func(t *testing.T) {
+		phone, err := ParsePhoneNumber("(978) 555-0145", 1)
+		require.NoError(t, err)
+		assert.Equal(t, "+19785550145", phone.Raw)
+
+		// Trunk prefix is removed
+		phone, err = ParsePhoneNumber("1 978 555 0145", 1)
+		require.NoError(t, err)
+		assert.Equal(t, int64(9785550145), phone.Number)
+
+		phone, err = ParsePhoneNumber("020 7123 4567", 44)
+		require.NoError(t, err)
+		assert.Equal(t, "+442071234567", phone.Raw)
+
+		// Italian numbers keep the leading zero
+		phone, err = ParsePhoneNumber("06 1234 5678", 39)
+		require.NoError(t, err)
+		assert.Equal(t, "+390612345678", phone.Raw)
+		assert.Equal(t, "+390612345678", phone.E164())
+		assert.Equal(t, int64(612345678), phone.Number) // The number itself cannot keep the zero
+		assert.Empty(t, phone.Display)                  // No known display format
+		assert.Empty(t, phone.DisplayInternational)
+
+		// Also after a JSON round trip, or from the display of an API phone
+		data, err := json.Marshal(phone)
+		require.NoError(t, err)
+		decoded := new(Phone)
+		require.NoError(t, json.Unmarshal(data, decoded))
+		assert.Equal(t, "+390612345678", decoded.E164())
+		assert.Equal(t, "+390612345678", (&Phone{CountryCode: 39, Number: 612345678, DisplayInternational: "+39 06 1234 5678"}).E164())
+		assert.Equal(t, "+390612345678x7", phoneKey(&Phone{CountryCode: 39, Number: 612345678, Display: "06 1234 5678", Extension: 7}))
+	})
+
+	t.Run("normalized key", func(t *testing.T) {
+		assert.Equal(t, "+19785550145x12", phoneKey(&Phone{CountryCode: 1, Number: 9785550145, Extension: 12}))
+		assert.Equal(t, "+19785550145x12", phoneKey(&Phone{Raw: "+1 (978) 555-0145 ext. 12"}))
+		assert.Equal(t, "+19785550145", phoneKey(&Phone{DisplayInternational: "+1 978-555-0145"}))
+		assert.Equal(t, "9785550145", phoneKey(&Phone{Raw: "(978) 555-0145"}))
+		assert.Empty(t, phoneKey(&Phone{Raw: "call me maybe"}))
+		assert.Empty(t, (&Phone{Raw: "(978) 555-0145"}).E164())
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		tests := []struct {
+			input              string
+			defaultCountryCode int
+			err                error
+		}{
+			{"", 1, ErrInvalidPhoneNumber},
+			{"12", 1, ErrInvalidPhoneNumber},
+			{"1-800-FLOWERS", 1, ErrInvalidPhoneNumber},
+			{"+1 978 555 0145 555 0145", 0, ErrInvalidPhoneNumber},
+			{"978-555-0145", 0, ErrMissingCountryCode},
+			{"978-555-0145", 999, ErrUnknownCountryCode},
+			{"+999 1234 5678", 0, ErrUnknownCountryCode},
+			{"+1 555-0145", 0, ErrInvalidPhoneLength},
+			{"+44 20 7123 4567 89", 0, ErrInvalidPhoneLength},
+		}
+		for _, test := range tests {
+			t.Run(test.input, func(t *testing.T) {
+				phone, err := ParsePhoneNumber(test.input, test.defaultCountryCode)
+				require.ErrorIs(t, err, test.err)
+				assert.Nil(t, phone)
+			})
+		}
+	})
+}
+
+// TestAddPhone_Validation will test the country plan validation of the Add methods
+func TestAddPhone_Validation(t *testing.T) {
+	t.Parallel()
+
+	t.Run("add phone", func(t *testing.T) {
+		person := NewPerson()
+		require.NoError(t, person.AddPhone(123456789, 33))
+		assert.Equal(t, "01 23 45 67 89", person.Phones[0].Display)
+		assert.Equal(t, "+33 1 23 45 67 89", person.Phones[0].DisplayInternational)
+		assert.Empty(t, person.Phones[0].Raw)
+
+		// Without a known display format (instead of a wrong one)
+		require.NoError(t, person.AddPhone(2079460958, 44))
+		assert.Empty(t, person.Phones[1].Display)
+		assert.Empty(t, person.Phones[1].DisplayInternational)
+		assert.Equal(t, "+442079460958", person.Phones[1].E164())
+
+		require.ErrorIs(t, person.AddPhone(5550145, 1), ErrInvalidPhoneLength)
+		require.ErrorIs(t, person.AddPhone(9785550145, 999), ErrUnknownCountryCode)
+		require.ErrorIs(t, person.AddPhone(-9785550145, 1), ErrInvalidPhoneNumber)
+		assert.Len(t, person.Phones, 2)
+	})
+
+	t.Run("add phone raw", func(t *testing.T) {
+		person := NewPerson()
+		require.NoError(t, person.AddPhoneRaw("+1 (978) 555-0145 ext. 12"))
+		assert.Equal(t, "+19785550145", person.Phones[0].Raw)
+		assert.Equal(t, 12, person.Phones[0].Extension)
+
+		// National numbers are validated for the default country
+		require.NoError(t, person.AddPhoneRaw("(978) 555-0145 ext 5"))
+		assert.Equal(t, "+19785550145", person.Phones[1].Raw)
+		assert.Equal(t, DefaultCountryCode, person.Phones[1].CountryCode)
+		assert.Equal(t, 5, person.Phones[1].Extension)
+		require.NoError(t, person.AddPhoneRaw("+44 20 7946 0958"))
+		assert.Equal(t, "+442079460958", person.Phones[2].Raw)
+
+		require.ErrorIs(t, person.AddPhoneRaw("1234"), ErrInvalidPhoneLength)
+		require.ErrorIs(t, person.AddPhoneRaw("555-1234"), ErrInvalidPhoneLength)
+		require.ErrorIs(t, person.AddPhoneRaw("020 7946 0958"), ErrInvalidPhoneLength) // Needs the country calling code
+		require.ErrorIs(t, person.AddPhoneRaw("+1 555-0145"), ErrInvalidPhoneLength)
+		require.ErrorIs(t, person.AddPhoneRaw("call me maybe"), ErrInvalidPhoneNumber)
+		require.ErrorIs(t, person.AddPhoneRaw("555-1234 or 555-1235"), ErrInvalidPhoneNumber)
+		assert.Len(t, person.Phones, 3)
+	})
+}
+
+// ExampleParsePhoneNumber example using ParsePhoneNumber()
+func ExampleParsePhoneNumber() {
+	phone, _ := ParsePhoneNumber("+1 (978) 555-0145 ext. 12", 0)
+	fmt.Println(phone.E164(), phone.Extension, phone.DisplayInternational)
+	// Output:+19785550145 12 +1 978-555-0145 x12
+}
+
+// BenchmarkParsePhoneNumber benchmarks the method ParsePhoneNumber()
+func BenchmarkParsePhoneNumber(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, _ = ParsePhoneNumber("+1 (978) 555-0145 ext. 12", 0)
+	}
+}
@@ -5,15 +5,17 @@ package pipl
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"net/url"
+	"sync"
 )
 
 // Search takes a person object (filled with search terms) and returns the
 // results in the form of a Response struct. If successful, the response struct
 // will contain the results, and err will be nil. If an error occurs, the struct pointer
-// will be nil, and you should check err for additional information. This method will only
+// will be nil, and you should check err for additional information (with DecodeModeStrict,
+// an *UnknownFieldsError is returned along with the response). This method will only
 // return one full person, and a preview of possible people if < 100% match. Use the SearchAllPossiblePeople()
 // method to get all the details when searching.
 func (c *Client) Search(ctx context.Context, searchPerson *Person) (*Response, error) {
@@ -22,8 +24,8 @@ func (c *Client) Search(ctx context.Context, searchPerson *Person) (*Response, e
 		return nil, ErrDoesNotMeetMinimumCriteria
 	}
 
-	// Start the post data
-	postData := url.Values{}
+	// Start the post data (with the search parameters)
+	postData := searchParameterValues(c.options.searchOptions.Search)
 
 	// Add the API key (always - API is required by default)
 	postData.Add(fieldAPIKey, c.options.apiKey)
@@ -33,48 +35,8 @@ func (c *Client) Search(ctx context.Context, searchPerson *Person) (*Response, e
 		postData.Add(fieldPretty, valueFalse)
 	}
 
-	// Should we show sources?
-	if c.options.searchOptions.Search.ShowSources != ShowSourcesNone {
-		postData.Add(fieldShowSources, string(c.options.searchOptions.Search.ShowSources))
-	}
-
-	// Add match requirements?
-	if c.options.searchOptions.Search.MatchRequirements != MatchRequirementsNone {
-		postData.Add(fieldMatchRequirements, string(c.options.searchOptions.Search.MatchRequirements))
-	}
-
-	// Add source category requirements?
-	if c.options.searchOptions.Search.SourceCategoryRequirements != SourceCategoryRequirementsNone {
-		postData.Add(fieldSourceCategoryRequirements, string(c.options.searchOptions.Search.SourceCategoryRequirements))
-	}
-
-	// Custom minimum match
-	if c.options.searchOptions.Search.MinimumMatch != MinimumMatch {
-		postData.Add(fieldMinimumMatch, fmt.Sprintf("%v", c.options.searchOptions.Search.MinimumMatch))
-	}
-
-	// Set the "hide sponsors" flag (default is false)
-	if c.options.searchOptions.Search.HideSponsored {
-		postData.Add(fieldHideSponsored, valueTrue)
-	}
-
-	// Set the "infer persons" flag (default is false)
-	if c.options.searchOptions.Search.InferPersons {
-		postData.Add(fieldInferPersons, valueTrue)
-	}
-
-	// Ask for the top match?
-	if c.options.searchOptions.Search.TopMatch {
-		postData.Add(fieldTopMatch, valueTrue)
-	}
-
-	// Set the live feeds flag (default is true)
-	if !c.options.searchOptions.Search.LiveFeeds {
-		postData.Add(fieldLiveFeeds, valueFalse)
-	}
-
-	// Parse the search object
-	personJSON, err := json.Marshal(searchPerson)
+	// Parse the search object (canonical, without empty values)
+	personJSON, err := CanonicalQuery(searchPerson)
 	if err != nil { // This should NEVER error out since the struct is being generated
 		return nil, err
 	}
@@ -83,43 +45,95 @@ func (c *Client) Search(ctx context.Context, searchPerson *Person) (*Response, e
 	postData.Add(fieldPerson, string(personJSON))
 
 	// Fire the request
-	var response *Response
-	response, err = httpRequest(ctx, c, searchAPIEndpoint, &postData)
-	if err != nil {
-		return nil, err
-	} else if len(response.Error) > 0 {
-		return nil, fmt.Errorf("%w: %s", ErrAPIResponse, response.Error)
-	}
-	return response, nil
+	return httpRequest(ctx, c, searchAPIEndpoint, &postData)
 }
 
 // SearchAllPossiblePeople takes a person object (filled with search terms) and returns the
 // results in the form of a Response struct. If possible people are found, they are also
 // looked up using the SearchByPointer()
+//
+// The search pointers are resolved concurrently (see PossiblePersonsSettings). If some of
+// them fail, the response is still returned (failed possible persons stay as previews)
+// along with a *PartialResultError that holds the error for each failed possible person.
+// With DecodeModeStrict, the possible persons are still expanded, and the *UnknownFieldsError
+// of the search (and of each search pointer) is returned along with the response.
 func (c *Client) SearchAllPossiblePeople(ctx context.Context, searchPerson *Person) (response *Response, err error) {
-	// Lookup the person(s)
-	if response, err = c.Search(ctx, searchPerson); err != nil {
-		return response, err
+	// Lookup the person(s), the unknown fields of strict decoding are returned at the end
+	var decodeErr error
+	if response, err = c.Search(ctx, searchPerson); response == nil {
+		return nil, err
 	}
+	decodeErr = err
 
 	// When multiple PossiblePersons are returned, we get a "preview" of each of them (< 100% match confidence)
-	if response.PersonsCount > 1 {
-		for index, person := range response.PossiblePersons {
+	if response.PersonsCount <= 1 || len(response.PossiblePersons) == 0 {
+		return response, decodeErr
+	}
+
+	// Get the settings (defaults if not set)
+	settings := c.options.searchOptions.PossiblePersons
+	if settings == nil {
+		settings = DefaultSearchOptions().PossiblePersons
+	}
+
+	// Limit the number of (billed) expansions
+	expansions := len(response.PossiblePersons)
+	if settings.MaxExpansions > 0 && settings.MaxExpansions < expansions {
+		expansions = settings.MaxExpansions
+	}
+
+	var (
+		mu        sync.Mutex
+		partial   = &PartialResultError{Errors: make(map[int]error)}
+		semaphore = make(chan struct{}, batchConcurrency(settings.Concurrency, expansions))
+		wg        sync.WaitGroup
+	)
+
+	for index := 0; index < expansions; index++ {
+		wg.Add(1)
+		go func(index int, searchPointer string) {
+			defer wg.Done()
+
+			// Wait for a free slot
+			select {
+			case semaphore <- struct{}{}:
+				defer func() { <-semaphore }()
+			case <-ctx.Done():
+				mu.Lock()
+				partial.Errors[index] = ctx.Err()
+				mu.Unlock()
+				return
+			}
 
 			// In order to get the full info on each, we need to a follow-up query
 			// to pull a full person profile by search pointer
-			searchPointer := person.SearchPointer
-			var searchResponse *Response
-			if searchResponse, err = c.SearchByPointer(ctx, searchPointer); err != nil {
-				return response, err
+			searchResponse, searchErr := c.SearchByPointer(ctx, searchPointer)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if searchErr != nil {
+				partial.Errors[index] = fmt.Errorf("search pointer %s: %w", searchPointer, searchErr)
+			}
+			if searchResponse == nil {
+				return
 			}
 
-			// Replace the preview with the full details
+			// Replace the preview with the full details (keeping the match score of the preview,
+			// the full profile is always a 100% match for its own search pointer)
+			match := response.PossiblePersons[index].Match
 			response.PossiblePersons[index] = searchResponse.Person
-		}
+			response.PossiblePersons[index].Match = match
+		}(index, response.PossiblePersons[index].SearchPointer)
 	}
+	wg.Wait()
 
-	return response, err
+	switch {
+	case len(partial.Errors) > 0 && decodeErr != nil:
+		return response, errors.Join(decodeErr, partial)
+	case len(partial.Errors) > 0:
+		return response, partial
+	}
+	return response, decodeErr
 }
 
 // SearchByPointer takes a search pointer string and returns the full
@@ -145,11 +159,57 @@ func (c *Client) SearchByPointer(ctx context.Context, searchPointer string) (*Re
 	postData.Add(fieldSearchPointer, searchPointer)
 
 	// Fire the request
-	response, err := httpRequest(ctx, c, searchAPIEndpoint, &postData)
-	if err != nil {
-		return nil, err
-	} else if len(response.Error) > 0 {
-		return nil, fmt.Errorf("%w: %s", ErrAPIResponse, response.Error)
+	return httpRequest(ctx, c, searchAPIEndpoint, &postData)
+}
+
+// searchParameterValues returns the post data of the search parameters that change the results
+// (everything except the API key and the pretty flag)
+func searchParameterValues(params *SearchParameters) url.Values {
+	postData := url.Values{}
+
+	// Should we show sources?
+	if params.ShowSources != ShowSourcesNone {
+		postData.Add(fieldShowSources, string(params.ShowSources))
+	}
+
+	// Add match requirements?
+	if params.MatchRequirements != MatchRequirementsNone {
+		postData.Add(fieldMatchRequirements, string(params.MatchRequirements))
+	}
+
+	// Add source category requirements?
+	if params.SourceCategoryRequirements != SourceCategoryRequirementsNone {
+		postData.Add(fieldSourceCategoryRequirements, string(params.SourceCategoryRequirements))
+	}
+
+	// Custom minimum match
+	if params.MinimumMatch != MinimumMatch {
+		postData.Add(fieldMinimumMatch, fmt.Sprintf("%v", params.MinimumMatch))
+	}
+
+	// Custom minimum probability (for inferred data)
+	if params.MinimumProbability != MinimumProbability {
+		postData.Add(fieldMinimumProbability, fmt.Sprintf("%v", params.MinimumProbability))
+	}
+
+	// Set the "hide sponsors" flag (default is false)
+	if params.HideSponsored {
+		postData.Add(fieldHideSponsored, valueTrue)
+	}
+
+	// Set the "infer persons" flag (default is false)
+	if params.InferPersons {
+		postData.Add(fieldInferPersons, valueTrue)
+	}
+
+	// Ask for the top match?
+	if params.TopMatch {
+		postData.Add(fieldTopMatch, valueTrue)
+	}
+
+	// Set the live feeds flag (default is true)
+	if !params.LiveFeeds {
+		postData.Add(fieldLiveFeeds, valueFalse)
 	}
-	return response, nil
+	return postData
 }
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -306,9 +307,80 @@ func TestClient_SearchAllPossiblePeople(t *testing.T) {
 		require.Equal(t, testEmail, response.Query.Emails[0].Address)
 	})
 
-	t.Run("basic search, multiple possible persons", func(_ *testing.T) {
-		// todo: add new JSON with possible persons
+	t.Run("basic search, multiple possible persons", func(t *testing.T) {
+		mock := &searchResponse{searchFile: "response_possible_persons.json"}
+		options := DefaultSearchOptions()
+		options.Thumbnail.Enabled = true
+		c := NewClient(WithAPIKey(testKey), WithHTTPClient(mock), WithSearchOptions(options))
+		require.NotNil(t, c)
+
+		ctx := context.Background()
+		searchObject := NewPerson()
+		err := searchObject.AddName("clark", "", "kent", "", "")
+		require.NoError(t, err)
+
+		var response *Response
+		response, err = c.SearchAllPossiblePeople(ctx, searchObject)
+		require.Error(t, err)
+		require.ErrorIs(t, err, ErrAPIResponse)
+		require.NotNil(t, response)
+		require.Len(t, response.PossiblePersons, 3)
+		assert.Equal(t, int32(4), mock.calls.Load())
+
+		// Failed pointer is reported (and the preview is kept)
+		var partial *PartialResultError
+		require.ErrorAs(t, err, &partial)
+		require.Len(t, partial.Errors, 1)
+		require.ErrorIs(t, partial.Errors[1], ErrAPIResponse)
+		assert.Contains(t, err.Error(), "possible person 1")
+		assert.Equal(t, "Clark J Kent", response.PossiblePersons[1].Names[0].Display)
+
+		// Successful pointers are replaced by the full person
+		assert.Equal(t, testSearchPointer, response.PossiblePersons[0].SearchPointer)
+		assert.Len(t, response.PossiblePersons[0].Emails, 4)
+		assert.Equal(t, "2906090343183157724859920008073008866", response.PossiblePersons[2].SearchPointer)
+		assert.Len(t, response.PossiblePersons[2].Emails, 4)
+
+		// The match score of the preview is kept
+		assert.InDelta(t, 0.62, response.PossiblePersons[0].Match, 0.001)
+		assert.InDelta(t, 0.13, response.PossiblePersons[2].Match, 0.001)
+	})
 
-		// todo: test thumbnail generation of persons
+	t.Run("possible persons, thumbnails on previews", func(t *testing.T) {
+		options := DefaultSearchOptions()
+		options.Thumbnail.Enabled = true
+		c := NewClient(WithAPIKey(testKey), WithHTTPClient(&searchResponse{searchFile: "response_possible_persons.json"}), WithSearchOptions(options))
+
+		response, err := c.Search(context.Background(), &Person{Emails: []Email{{Address: testEmail}}})
+		require.NoError(t, err)
+		require.Len(t, response.PossiblePersons, 3)
+		assert.Contains(t, response.PossiblePersons[2].Images[0].ThumbnailURL, testThumbnailToken)
+	})
+
+	t.Run("possible persons, max expansions", func(t *testing.T) {
+		mock := &searchResponse{searchFile: "response_possible_persons.json"}
+		options := DefaultSearchOptions()
+		options.PossiblePersons = &PossiblePersonsSettings{Concurrency: 1, MaxExpansions: 1}
+		c := NewClient(WithAPIKey(testKey), WithHTTPClient(mock), WithSearchOptions(options))
+
+		response, err := c.SearchAllPossiblePeople(context.Background(), &Person{Emails: []Email{{Address: testEmail}}})
+		require.NoError(t, err)
+		require.Len(t, response.PossiblePersons, 3)
+		assert.Equal(t, int32(2), mock.calls.Load())
+		assert.Len(t, response.PossiblePersons[0].Emails, 4)
+		assert.Empty(t, response.PossiblePersons[2].Emails)
+	})
+
+	t.Run("possible persons, missing settings use defaults", func(t *testing.T) {
+		mock := &searchResponse{searchFile: "response_possible_persons.json"}
+		c := NewClient(WithAPIKey(testKey), WithHTTPClient(mock), WithSearchOptions(&SearchOptions{
+			Search:    DefaultSearchOptions().Search,
+			Thumbnail: &ThumbnailSettings{},
+		}))
+
+		response, err := c.SearchAllPossiblePeople(context.Background(), &Person{Emails: []Email{{Address: testEmail}}})
+		require.Error(t, err)
+		require.NotNil(t, response)
+		assert.Equal(t, int32(4), mock.calls.Load())
 	})
 }
@@ -0,0 +1,113 @@
+package pipl
+
+import (
+	"context"
+	"iter"
+	"sync"
+)
+
+// PossiblePersonsResolver lazily resolves the possible persons of a response. Each possible
+// person starts as a preview, and the full profile is only fetched (SearchByPointer) when asked.
+// Full profiles are memoized by search pointer, so a pointer is never fetched twice.
+//
+// It is safe for concurrent use.
+type PossiblePersonsResolver struct {
+	cache    map[string]*resolvedPerson
+	mu       sync.Mutex
+	response *Response
+	service  SearchService
+}
+
+// resolvedPerson is a memoized full profile (the lock prevents concurrent fetches of the same pointer)
+type resolvedPerson struct {
+	mu     sync.Mutex
+	person *Person
+}
+
+// PossiblePerson is a possible person preview from a response, with the full profile fetched on demand
+type PossiblePerson struct {
+	Index    int     // Index in Response.PossiblePersons
+	Preview  *Person // Preview from the original response (no extra query)
+	resolver *PossiblePersonsResolver
+}
+
+// NewPossiblePersonsResolver will create a new resolver for the possible persons of the response
+func NewPossiblePersonsResolver(service SearchService, response *Response) *PossiblePersonsResolver {
+	return &PossiblePersonsResolver{
+		cache:    make(map[string]*resolvedPerson),
+		response: response,
+		service:  service,
+	}
+}
+
+// All returns an iterator over the possible persons (previews only, nothing is fetched
+// until PossiblePerson.Full() is called). Stop iterating to avoid resolving the rest.
+func (r *PossiblePersonsResolver) All() iter.Seq2[int, *PossiblePerson] {
+	return func(yield func(int, *PossiblePerson) bool) {
+		if r.response == nil {
+			return
+		}
+		for index := range r.response.PossiblePersons {
+			possible := &PossiblePerson{
+				Index:    index,
+				Preview:  &r.response.PossiblePersons[index],
+				resolver: r,
+			}
+			if !yield(index, possible) {
+				return
+			}
+		}
+	}
+}
+
+// Resolved returns true if the full profile for the search pointer was already fetched
+func (r *PossiblePersonsResolver) Resolved(searchPointer string) bool {
+	entry := r.entry(searchPointer)
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	return entry.person != nil
+}
+
+// Resolve returns the full profile for the search pointer (fetched once, then memoized).
+// Errors are not memoized, the next call will try again. With DecodeModeStrict, the profile is
+// returned (and memoized) along with the *UnknownFieldsError.
+func (r *PossiblePersonsResolver) Resolve(ctx context.Context, searchPointer string) (*Person, error) {
+	entry := r.entry(searchPointer)
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	// Already fetched
+	if entry.person != nil {
+		return entry.person, nil
+	}
+
+	// Fetch the full profile
+	response, err := r.service.SearchByPointer(ctx, searchPointer)
+	if searchFailed(response, err) {
+		return nil, err
+	}
+	entry.person = &response.Person
+	return entry.person, err
+}
+
+// entry returns the memo entry for the search pointer (created if missing)
+func (r *PossiblePersonsResolver) entry(searchPointer string) *resolvedPerson {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entry, ok := r.cache[searchPointer]
+	if !ok {
+		entry = new(resolvedPerson)
+		r.cache[searchPointer] = entry
+	}
+	return entry
+}
+
+// Full returns the full profile of the possible person (using SearchByPointer the first time)
+func (p *PossiblePerson) Full(ctx context.Context) (*Person, error) {
+	return p.resolver.Resolve(ctx, p.Preview.SearchPointer)
+}
+
+// Resolved returns true if the full profile was already fetched
+func (p *PossiblePerson) Resolved() bool {
+	return p.resolver.Resolved(p.Preview.SearchPointer)
+}
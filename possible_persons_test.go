@@ -0,0 +1,113 @@
+package pipl
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPossiblePersonsResolver will test the lazy possible person resolver
+func TestPossiblePersonsResolver(t *testing.T) {
+	t.Parallel()
+
+	response, err := loadResponseData("response_possible_persons.json")
+	require.NoError(t, err)
+
+	t.Run("nil response", func(t *testing.T) {
+		resolver := NewPossiblePersonsResolver(NewClient(WithHTTPClient(&searchResponse{})), nil)
+		for range resolver.All() {
+			t.Fatal("should not yield")
+		}
+	})
+
+	t.Run("previews without fetching", func(t *testing.T) {
+		mock := &searchResponse{}
+		resolver := NewPossiblePersonsResolver(NewClient(WithAPIKey(testKey), WithHTTPClient(mock)), response)
+
+		var names []string
+		for index, possible := range resolver.All() {
+			assert.Equal(t, index, possible.Index)
+			assert.False(t, possible.Resolved())
+			names = append(names, possible.Preview.Names[0].Display)
+		}
+		assert.Equal(t, []string{"Clark Kent", "Clark J Kent", "Kal El"}, names)
+		assert.Equal(t, int32(0), mock.calls.Load())
+	})
+
+	t.Run("stop at the first match, memoized", func(t *testing.T) {
+		mock := &searchResponse{}
+		resolver := NewPossiblePersonsResolver(NewClient(WithAPIKey(testKey), WithHTTPClient(mock)), response)
+
+		var found *Person
+		for _, possible := range resolver.All() {
+			if possible.Preview.Names[0].First != "Clark" {
+				continue
+			}
+			person, fullErr := possible.Full(context.Background())
+			require.NoError(t, fullErr)
+			if len(person.Emails) > 0 {
+				found = person
+				break
+			}
+		}
+		require.NotNil(t, found)
+		assert.Equal(t, testSearchPointer, found.SearchPointer)
+		assert.Equal(t, int32(1), mock.calls.Load())
+
+		// Second time is from memory
+		for _, possible := range resolver.All() {
+			assert.True(t, possible.Resolved())
+			person, fullErr := possible.Full(context.Background())
+			require.NoError(t, fullErr)
+			assert.Same(t, found, person)
+			break
+		}
+		assert.Equal(t, int32(1), mock.calls.Load())
+	})
+
+	t.Run("failed pointer is not memoized", func(t *testing.T) {
+		mock := &searchResponse{}
+		resolver := NewPossiblePersonsResolver(NewClient(WithAPIKey(testKey), WithHTTPClient(mock)), response)
+
+		pointer := response.PossiblePersons[1].SearchPointer
+		_, resolveErr := resolver.Resolve(context.Background(), pointer)
+		require.ErrorIs(t, resolveErr, ErrAPIResponse)
+		assert.False(t, resolver.Resolved(pointer))
+
+		_, resolveErr = resolver.Resolve(context.Background(), pointer)
+		require.ErrorIs(t, resolveErr, ErrAPIResponse)
+		assert.Equal(t, int32(2), mock.calls.Load())
+	})
+
+	t.Run("strict mode returns the profile with the unknown fields", func(t *testing.T) {
+		c := NewClient(WithAPIKey(testKey), WithHTTPClient(&rawResponse{body: testUnknownFieldsJSON}),
+			WithDecodeMode(DecodeModeStrict))
+		resolver := NewPossiblePersonsResolver(c, response)
+
+		pointer := response.PossiblePersons[0].SearchPointer
+		person, resolveErr := resolver.Resolve(context.Background(), pointer)
+		require.ErrorIs(t, resolveErr, ErrUnknownFields)
+		require.NotNil(t, person)
+		assert.Equal(t, "Clark Kent", person.Names[0].Display)
+		assert.True(t, resolver.Resolved(pointer))
+	})
+
+	t.Run("concurrent resolves fetch once", func(t *testing.T) {
+		mock := &searchResponse{}
+		resolver := NewPossiblePersonsResolver(NewClient(WithAPIKey(testKey), WithHTTPClient(mock)), response)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, _ = resolver.Resolve(context.Background(), testSearchPointer)
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, int32(1), mock.calls.Load())
+	})
+}
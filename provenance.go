@@ -0,0 +1,233 @@
+package pipl
+
+import (
+	"reflect"
+	"strconv"
+)
+
+// Provenance links every value of the person (and the possible persons) to the sources
+// asserting it (see Response.Provenance)
+type Provenance struct {
+	Values []*ValueProvenance                     `json:"values"`
+	keys   map[string]map[string]*ValueProvenance // person position -> field and key -> provenance
+	paths  map[string]map[string]*ValueProvenance // person position -> path -> provenance
+}
+
+// ValueProvenance is the sources backing a single person value, grouped by category and domain
+//
+// DO NOT CHANGE ORDER - Optimized for memory (malign)
+type ValueProvenance struct {
+	Categories map[SourceCategory]int `json:"categories"`      // Number of sources by @category
+	Domains    map[string]int         `json:"domains"`         // Number of sources by @domain
+	Sources    []*Source              `json:"-"`               // The sources asserting the value
+	SourceIDs  []string               `json:"source_ids"`      // The @id of the sources
+	Field      string                 `json:"field"`           // Field name, for example "emails"
+	Key        string                 `json:"key"`             // Normalized value, for example "clark@example.com"
+	Path       string                 `json:"path"`            // Path in the person, for example "emails[0]"
+	Person     string                 `json:"person"`          // Position of the person, "person" or "possible_persons[0]"
+	PersonID   GUID                   `json:"person_id"`       // The @id of the person
+	Count      int                    `json:"count"`           // Number of sources
+	Premium    int                    `json:"premium_count"`   // Number of premium sources
+	Sponsored  int                    `json:"sponsored_count"` // Number of sponsored sources
+}
+
+// Provenance builds the provenance index of the response: every value of the person and the
+// possible persons is linked to the sources (with the same @person_id) that assert the same
+// (normalized) value. Sources are only returned with show_sources (see SearchParameters.ShowSources).
+//
+// Persons are indexed by their position in the response: "person" for the person, and
+// "possible_persons[0]" (and so on) for the possible persons. If a person has no @id, all the
+// sources are used.
+func (r *Response) Provenance() *Provenance {
+	p := newProvenance()
+	p.addPerson(provenancePerson, &r.Person, r.Sources)
+	for index := range r.PossiblePersons {
+		p.addPerson(possiblePersonPosition(index), &r.PossiblePersons[index], r.Sources)
+	}
+	return p
+}
+
+// provenancePerson is the position of the person of the response
+const provenancePerson = "person"
+
+// possiblePersonPosition returns the position of a possible person of the response
+func possiblePersonPosition(index int) string {
+	return "possible_persons[" + strconv.Itoa(index) + "]"
+}
+
+// newProvenance will create an empty provenance index
+func newProvenance() *Provenance {
+	return &Provenance{
+		Values: []*ValueProvenance{},
+		keys:   make(map[string]map[string]*ValueProvenance),
+		paths:  make(map[string]map[string]*ValueProvenance),
+	}
+}
+
+// At returns the provenance of the value at the path (for example, "emails[0]" or "dob") of the
+// person at the position ("person" or "possible_persons[0]"), or nil
+func (p *Provenance) At(person, path string) *ValueProvenance {
+	return p.paths[person][path]
+}
+
+// For returns the provenance of the value (for example, an Email of the person) of the person at
+// the position ("person" or "possible_persons[0]"), matched by its normalized value, or nil if the
+// person does not have the value
+func (p *Provenance) For(person string, value any) *ValueProvenance {
+	field, key := provenanceKey(value)
+	if len(key) == 0 {
+		return nil
+	}
+	return p.keys[person][field+":"+key]
+}
+
+// addPerson adds the values of the person (at the position), linked to the sources of the person
+func (p *Provenance) addPerson(position string, person *Person, sources []Source) {
+	// Only the sources of this person
+	var personSources []*Source
+	for index := range sources {
+		if len(person.ID) == 0 || sources[index].PersonID == person.ID {
+			personSources = append(personSources, &sources[index])
+		}
+	}
+
+	addProvenance(p, position, person, personSources, "addresses", person.Addresses, func(s *Source) []Address { return s.Addresses }, addressKey)
+	addProvenance(p, position, person, personSources, "dob", singleValue(person.DateOfBirth), func(s *Source) []DateOfBirth { return []DateOfBirth{s.DateOfBirth} }, dobKey)
+	addProvenance(p, position, person, personSources, "educations", person.Educations, func(s *Source) []Education { return s.Educations }, educationKey)
+	addProvenance(p, position, person, personSources, "emails", person.Emails, func(s *Source) []Email { return s.Emails }, emailKey)
+	addProvenance(p, position, person, personSources, "ethnicities", person.Ethnicities, func(s *Source) []Ethnicity { return s.Ethnicities }, ethnicityKey)
+	addProvenance(p, position, person, personSources, "gender", singleValue(person.Gender), func(s *Source) []Gender { return []Gender{s.Gender} }, genderKey)
+	addProvenance(p, position, person, personSources, "images", person.Images, func(s *Source) []Image { return s.Images }, imageKey)
+	addProvenance(p, position, person, personSources, "jobs", person.Jobs, func(s *Source) []Job { return s.Jobs }, jobKey)
+	addProvenance(p, position, person, personSources, "languages", person.Languages, func(s *Source) []Language { return s.Languages }, languageKey)
+	addProvenance(p, position, person, personSources, "names", person.Names, func(s *Source) []Name { return s.Names }, nameKey)
+	addProvenance(p, position, person, personSources, "origin_countries", person.OriginCountries, func(s *Source) []OriginCountry { return s.OriginCountries }, originCountryKey)
+	addProvenance(p, position, person, personSources, "phones", person.Phones, func(s *Source) []Phone { return s.Phones }, phoneKey)
+	addProvenance(p, position, person, personSources, "relationships", person.Relationships, func(s *Source) []Relationship { return s.Relationships }, relationshipKey)
+	addProvenance(p, position, person, personSources, "urls", person.URLs, func(s *Source) []URL { return s.URLs }, urlKey)
+	addProvenance(p, position, person, personSources, "user_ids", person.UserIDs, func(s *Source) []UserID { return s.UserIDs }, userIDKey)
+	addProvenance(p, position, person, personSources, "usernames", person.Usernames, func(s *Source) []Username { return s.Usernames }, usernameKey)
+	addProvenance(p, position, person, personSources, "vehicles", person.Vehicles, func(s *Source) []Vehicle { return s.Vehicles }, vehicleKey)
+}
+
+// addProvenance adds the provenance of every value of the field (duplicate values share the provenance)
+func addProvenance[T any](p *Provenance, position string, person *Person, sources []*Source, field string,
+	values []T, get func(*Source) []T, key func(*T) string,
+) {
+	if len(values) == 0 {
+		return
+	}
+
+	// Index the sources by the normalized value
+	bySource := make(map[string][]*Source)
+	for _, source := range sources {
+		seen := make(map[string]bool)
+		for _, value := range get(source) {
+			if k := key(&value); len(k) > 0 && !seen[k] {
+				seen[k] = true
+				bySource[k] = append(bySource[k], source)
+			}
+		}
+	}
+
+	if p.keys[position] == nil {
+		p.keys[position] = make(map[string]*ValueProvenance)
+		p.paths[position] = make(map[string]*ValueProvenance)
+	}
+	for index := range values {
+		path := field
+		if field != "dob" && field != "gender" {
+			path += "[" + strconv.Itoa(index) + "]"
+		}
+
+		k := key(&values[index])
+		if len(k) == 0 {
+			continue
+		}
+		if existing, ok := p.keys[position][field+":"+k]; ok {
+			p.paths[position][path] = existing
+			continue
+		}
+
+		provenance := newValueProvenance(position, person.ID, field, k, path, bySource[k])
+		p.keys[position][field+":"+k] = provenance
+		p.paths[position][path] = provenance
+		p.Values = append(p.Values, provenance)
+	}
+}
+
+// newValueProvenance will create the provenance of a value from its sources
+func newValueProvenance(position string, personID GUID, field, key, path string, sources []*Source) *ValueProvenance {
+	provenance := &ValueProvenance{
+		Categories: make(map[SourceCategory]int),
+		Count:      len(sources),
+		Domains:    make(map[string]int),
+		Field:      field,
+		Key:        key,
+		Path:       path,
+		Person:     position,
+		PersonID:   personID,
+		SourceIDs:  make([]string, 0, len(sources)),
+		Sources:    sources,
+	}
+	for _, source := range sources {
+		provenance.Categories[source.Category]++
+		provenance.Domains[source.Domain]++
+		provenance.SourceIDs = append(provenance.SourceIDs, source.ID)
+		if source.Premium {
+			provenance.Premium++
+		}
+		if source.Sponsored {
+			provenance.Sponsored++
+		}
+	}
+	return provenance
+}
+
+// provenanceKey returns the field name and the normalized key of the value (or pointer to a value)
+func provenanceKey(value any) (string, string) {
+	if v := reflect.ValueOf(value); v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return "", ""
+		}
+		value = v.Elem().Interface()
+	}
+
+	switch v := value.(type) {
+	case Address:
+		return "addresses", addressKey(&v)
+	case DateOfBirth:
+		return "dob", dobKey(&v)
+	case Education:
+		return "educations", educationKey(&v)
+	case Email:
+		return "emails", emailKey(&v)
+	case Ethnicity:
+		return "ethnicities", ethnicityKey(&v)
+	case Gender:
+		return "gender", genderKey(&v)
+	case Image:
+		return "images", imageKey(&v)
+	case Job:
+		return "jobs", jobKey(&v)
+	case Language:
+		return "languages", languageKey(&v)
+	case Name:
+		return "names", nameKey(&v)
+	case OriginCountry:
+		return "origin_countries", originCountryKey(&v)
+	case Phone:
+		return "phones", phoneKey(&v)
+	case Relationship:
+		return "relationships", relationshipKey(&v)
+	case URL:
+		return "urls", urlKey(&v)
+	case UserID:
+		return "user_ids", userIDKey(&v)
+	case Username:
+		return "usernames", usernameKey(&v)
+	case Vehicle:
+		return "vehicles", vehicleKey(&v)
+	}
+	return "", ""
+}
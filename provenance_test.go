@@ -0,0 +1,149 @@
+package pipl
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestProvenanceResponse returns a response with sources for the person and a possible person
+func newTestProvenanceResponse() *Response {
+	return &Response{
+		Person: Person{
+			ID: "p1",
+			Emails: []Email{
+				{Address: "clark@example.com"},
+				{Address: "kent@example.com"},
+				{Address: "CLARK@example.com"},
+			},
+			Gender: &Gender{Content: "male"},
+			Phones: []Phone{{CountryCode: 1, Number: 9785550145}},
+		},
+		PossiblePersons: []Person{
+			{ID: "p2", Emails: []Email{{Address: "clark@example.com"}}},
+		},
+		Sources: []Source{
+			{
+				ID: "s1", PersonID: "p1", Category: SourceCategoryPersonalProfiles, Domain: "example.com",
+				Emails: []Email{{Address: "Clark@Example.com"}, {Address: "clark@example.com"}},
+				Gender: Gender{Content: "Male"},
+			},
+			{
+				ID: "s2", PersonID: "p1", Category: SourceCategoryBackgroundReports, Domain: "reports.example.com", Premium: true,
+				Emails: []Email{{Address: "clark@example.com"}},
+				Phones: []Phone{{DisplayInternational: "+1 978-555-0145"}},
+			},
+			{
+				ID: "s3", PersonID: "p1", Category: SourceCategoryPersonalProfiles, Domain: "example.com", Sponsored: true,
+				Emails: []Email{{Address: "clark@example.com"}},
+			},
+			{
+				ID: "s4", PersonID: "p2", Category: SourceCategoryWebPages, Domain: "example.org",
+				Emails: []Email{{Address: "clark@example.com"}},
+			},
+		},
+	}
+}
+
+// TestResponse_Provenance will test linking person values to their sources
+func TestResponse_Provenance(t *testing.T) {
+	t.Parallel()
+
+	t.Run("sources of an email", func(t *testing.T) {
+		response := newTestProvenanceResponse()
+		provenance := response.Provenance()
+
+		email := provenance.For("person", response.Person.Emails[0])
+		require.NotNil(t, email)
+		assert.Equal(t, "emails[0]", email.Path)
+		assert.Equal(t, 3, email.Count)
+		assert.Equal(t, []string{"s1", "s2", "s3"}, email.SourceIDs)
+		assert.Equal(t, map[SourceCategory]int{
+			SourceCategoryPersonalProfiles:  2,
+			SourceCategoryBackgroundReports: 1,
+		}, email.Categories)
+		assert.Equal(t, map[string]int{"example.com": 2, "reports.example.com": 1}, email.Domains)
+		assert.Equal(t, 1, email.Premium)
+		assert.Equal(t, 1, email.Sponsored)
+		assert.Same(t, &response.Sources[1], email.Sources[1])
+
+		// Duplicate values share the provenance
+		assert.Same(t, email, provenance.At("person", "emails[2]"))
+		assert.Same(t, email, provenance.For("person", &response.Person.Emails[2]))
+	})
+
+	t.Run("values without sources", func(t *testing.T) {
+		provenance := newTestProvenanceResponse().Provenance()
+		email := provenance.At("person", "emails[1]")
+		require.NotNil(t, email)
+		assert.Equal(t, 0, email.Count)
+		assert.Empty(t, email.SourceIDs)
+	})
+
+	t.Run("single values and phones", func(t *testing.T) {
+		provenance := newTestProvenanceResponse().Provenance()
+		assert.Equal(t, []string{"s1"}, provenance.At("person", "gender").SourceIDs)
+		assert.Equal(t, []string{"s2"}, provenance.At("person", "phones[0]").SourceIDs)
+	})
+
+	t.Run("possible persons only use their sources", func(t *testing.T) {
+		provenance := newTestProvenanceResponse().Provenance()
+		email := provenance.For("possible_persons[0]", Email{Address: "clark@example.com"})
+		require.NotNil(t, email)
+		assert.Equal(t, []string{"s4"}, email.SourceIDs)
+	})
+
+	t.Run("persons without an id", func(t *testing.T) {
+		response := &Response{
+			Person: Person{Emails: []Email{{Address: "clark@example.com"}}},
+			PossiblePersons: []Person{
+				{Emails: []Email{{Address: "clark@example.com"}}, Phones: []Phone{{Raw: "+1 978-555-0145"}}},
+				{Emails: []Email{{Address: "kent@example.com"}, {Address: "clark@example.com"}}},
+			},
+			Sources: []Source{{ID: "s1", Emails: []Email{{Address: "clark@example.com"}}}},
+		}
+		provenance := response.Provenance()
+		require.Len(t, provenance.Values, 5)
+
+		// Every person has its own provenance (and paths)
+		first := provenance.For("possible_persons[0]", Email{Address: "clark@example.com"})
+		second := provenance.For("possible_persons[1]", Email{Address: "clark@example.com"})
+		require.NotNil(t, first)
+		require.NotNil(t, second)
+		assert.NotSame(t, first, second)
+		assert.NotSame(t, provenance.For("person", Email{Address: "clark@example.com"}), first)
+		assert.Equal(t, "emails[0]", first.Path)
+		assert.Equal(t, "possible_persons[0]", first.Person)
+		assert.Equal(t, "emails[1]", second.Path)
+		assert.Equal(t, "possible_persons[1]", second.Person)
+		assert.Same(t, second, provenance.At("possible_persons[1]", "emails[1]"))
+		assert.Equal(t, "kent@example.com", provenance.At("possible_persons[1]", "emails[0]").Key)
+		assert.Nil(t, provenance.At("possible_persons[1]", "phones[0]"))
+		assert.Nil(t, provenance.At("person", "phones[0]"))
+	})
+
+	t.Run("unknown values", func(t *testing.T) {
+		provenance := newTestProvenanceResponse().Provenance()
+		assert.Nil(t, provenance.For("person", Email{Address: "lois@example.com"}))
+		assert.Nil(t, provenance.For("person", (*Email)(nil)))
+		assert.Nil(t, provenance.For("person", "clark@example.com"))
+		assert.Nil(t, provenance.At("possible_persons[1]", "emails[0]"))
+	})
+
+	t.Run("fixture", func(t *testing.T) {
+		response, err := loadResponseData("response_schema.json")
+		require.NoError(t, err)
+
+		provenance := response.Provenance()
+		vehicle := provenance.For("person", response.Person.Vehicles[0])
+		require.NotNil(t, vehicle)
+		assert.Equal(t, []string{"b2"}, vehicle.SourceIDs)
+		assert.Equal(t, 1, vehicle.Categories[SourceCategoryPublicRecords])
+
+		data, err := json.Marshal(provenance)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), `"source_ids":["b2"]`)
+	})
+}
@@ -0,0 +1,143 @@
+package pipl
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// CanonicalQuery will encode the search person as canonical JSON: empty values (including empty
+// structs such as "date_range":{}) are dropped, identifiers are normalized (for example, emails
+// are lower case), duplicate entries are removed and keys and entries are sorted.
+//
+// The same search terms always have the same encoding (see QueryHash), and it is used by Search().
+func CanonicalQuery(person *Person) ([]byte, error) {
+	if person == nil {
+		return []byte("{}"), nil
+	}
+
+	normalized := deepCopy(person)
+	normalizeQuery(normalized)
+
+	value, ok := canonicalValue(reflect.ValueOf(normalized).Elem())
+	if !ok {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(value)
+}
+
+// QueryHash returns a stable fingerprint (SHA-256, hex) of the search person and the search
+// parameters that change the results, for caching, de-duplication and audit logs.
+// The API key and the pretty flag are not part of the hash.
+func QueryHash(person *Person, params SearchParameters) (string, error) {
+	query, err := CanonicalQuery(person)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.New()
+	_, _ = hash.Write(query)
+	_, _ = hash.Write([]byte{'\n'})
+	_, _ = hash.Write([]byte(searchParameterValues(&params).Encode()))
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// normalizeQuery normalizes the identifiers of the search person (in place)
+func normalizeQuery(person *Person) {
+	for index := range person.Emails {
+		person.Emails[index].Address = strings.ToLower(strings.TrimSpace(person.Emails[index].Address))
+		person.Emails[index].AddressMD5 = strings.ToLower(strings.TrimSpace(person.Emails[index].AddressMD5))
+	}
+	for index := range person.Phones {
+		normalizePhoneRaw(&person.Phones[index])
+	}
+	for index := range person.URLs {
+		person.URLs[index].URL = normalizeURL(person.URLs[index].URL)
+	}
+}
+
+// normalizePhoneRaw replaces the raw number with the normalized number (E.164, or the digits of a
+// national number, see phoneKey), for example "(978) 555-0145 ext. 12" is "9785550145" (extension 12).
+// Raw numbers that cannot be parsed are only trimmed.
+func normalizePhoneRaw(phone *Phone) {
+	if len(phone.Raw) == 0 {
+		return
+	}
+	parsed := Phone{CountryCode: phone.CountryCode, Raw: phone.Raw}
+	countryCode, digits, extension := parsed.parts()
+	switch {
+	case len(digits) == 0:
+		phone.Raw = strings.TrimSpace(phone.Raw)
+		return
+	case countryCode > 0:
+		phone.Raw = parsed.E164()
+	default:
+		phone.Raw = digits
+	}
+	if phone.Extension == 0 {
+		phone.Extension = extension
+	}
+}
+
+// normalizeURL lower cases the scheme and host of the url
+func normalizeURL(value string) string {
+	value = strings.TrimSpace(value)
+	parsed, err := url.Parse(value)
+	if err != nil || len(parsed.Host) == 0 {
+		return value
+	}
+	parsed.Scheme = strings.ToLower(parsed.Scheme)
+	parsed.Host = strings.ToLower(parsed.Host)
+	return parsed.String()
+}
+
+// canonicalValue returns the canonical JSON value, or false if the value is empty
+func canonicalValue(value reflect.Value) (any, bool) {
+	switch value.Kind() { //nolint:exhaustive // Only the kinds used by the search person
+	case reflect.Pointer:
+		if value.IsNil() {
+			return nil, false
+		}
+		return canonicalValue(value.Elem())
+	case reflect.Struct:
+		fields := make(map[string]any)
+		for name, index := range structJSONFields(value.Type()) {
+			if field, ok := canonicalValue(value.Field(index)); ok {
+				fields[name] = field
+			}
+		}
+		return fields, len(fields) > 0
+	case reflect.Slice:
+		// Entries are sorted (and de-duplicated) by their canonical JSON
+		var entries []json.RawMessage
+		for index := 0; index < value.Len(); index++ {
+			entry, ok := canonicalValue(value.Index(index))
+			if !ok {
+				continue
+			}
+			data, err := json.Marshal(entry)
+			if err != nil {
+				continue
+			}
+			entries = append(entries, data)
+		}
+		slices.SortFunc(entries, func(a, b json.RawMessage) int { return bytes.Compare(a, b) })
+		entries = slices.CompactFunc(entries, func(a, b json.RawMessage) bool { return bytes.Equal(a, b) })
+		return entries, len(entries) > 0
+	case reflect.String:
+		text := strings.Join(strings.Fields(value.String()), " ")
+		return text, len(text) > 0
+	case reflect.Bool:
+		return true, value.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value.Int(), value.Int() != 0
+	case reflect.Float32, reflect.Float64:
+		return value.Interface(), value.Float() != 0
+	}
+	return value.Interface(), !value.IsZero()
+}
@@ -0,0 +1,170 @@
+package pipl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCanonicalQuery will test the canonical encoding of a search person
+func TestCanonicalQuery(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty values are dropped", func(t *testing.T) {
+		person := NewPerson()
+		require.NoError(t, person.AddJob("Reporter", "Daily Planet", "", "", ""))
+		require.NoError(t, person.AddEducation("", "Metropolis University", "", ""))
+
+		// The default encoding has empty structs
+		data, err := json.Marshal(person)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), `"date_range":{}`)
+
+		data, err = CanonicalQuery(person)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{
+			"educations": [{"school": "Metropolis University"}],
+			"jobs": [{"organization": "Daily Planet", "title": "Reporter"}]
+		}`, string(data))
+		assert.NotContains(t, string(data), "date_range")
+	})
+
+	t.Run("normalized and sorted", func(t *testing.T) {
+		first := NewPerson()
+		require.NoError(t, first.AddEmail(" Clark.Kent@Example.com "))
+		require.NoError(t, first.AddEmail("lois@example.com"))
+		require.NoError(t, first.AddPhoneRaw("(978) 555-0145"))
+		require.NoError(t, first.AddURL("HTTPS://Example.com/Clark"))
+		require.NoError(t, first.AddNameRaw("Clark   Kent"))
+
+		second := NewPerson()
+		require.NoError(t, second.AddNameRaw("Clark Kent"))
+		require.NoError(t, second.AddURL("https://example.com/Clark"))
+		require.NoError(t, second.AddPhoneRaw("978.555.0145"))
+		require.NoError(t, second.AddEmail("lois@example.com"))
+		require.NoError(t, second.AddEmail("clark.kent@example.com"))
+		require.NoError(t, second.AddEmail("lois@example.com")) // Duplicate
+
+		a, err := CanonicalQuery(first)
+		require.NoError(t, err)
+		b, err := CanonicalQuery(second)
+		require.NoError(t, err)
+		assert.Equal(t, string(a), string(b))
+		assert.Equal(t, `{"emails":[{"address":"clark.kent@example.com"},{"address":"lois@example.com"}],`+
+			`"names":[{"raw":"Clark Kent"}],"phones":[{"country_code":1,"display":"978-555-0145",`+
+			`"display_international":"+1 978-555-0145","number":9785550145,"raw":"+19785550145"}],"urls":[{"url":"https://example.com/Clark"}]}`, string(a))
+
+		// The person is not changed
+		assert.Equal(t, " Clark.Kent@Example.com ", first.Emails[0].Address)
+	})
+
+	t.Run("raw phones are normalized", func(t *testing.T) {
+		tests := []struct {
+			phone    Phone
+			expected Phone
+		}{
+			{Phone{Raw: "+1 978-555-0145 ext. 12 "}, Phone{Raw: "+19785550145", Extension: 12}},
+			{Phone{Raw: "+1 (978) 555-0145"}, Phone{Raw: "+19785550145"}},
+			{Phone{Raw: "555-1234 ext 5"}, Phone{Raw: "5551234", Extension: 5}},
+			{Phone{Raw: "06 1234 5678", CountryCode: 39}, Phone{Raw: "+390612345678", CountryCode: 39}},
+			{Phone{Raw: " call me "}, Phone{Raw: "call me"}},
+		}
+		for _, test := range tests {
+			normalizePhoneRaw(&test.phone)
+			assert.Equal(t, test.expected, test.phone)
+		}
+	})
+
+	t.Run("empty person", func(t *testing.T) {
+		data, err := CanonicalQuery(nil)
+		require.NoError(t, err)
+		assert.Equal(t, "{}", string(data))
+
+		data, err = CanonicalQuery(&Person{Jobs: []Job{{}}, Gender: &Gender{}})
+		require.NoError(t, err)
+		assert.Equal(t, "{}", string(data))
+	})
+}
+
+// TestQueryHash will test the query fingerprint
+func TestQueryHash(t *testing.T) {
+	t.Parallel()
+
+	newPerson := func(emails ...string) *Person {
+		person := NewPerson()
+		for _, email := range emails {
+			require.NoError(t, person.AddEmail(email))
+		}
+		return person
+	}
+	params := *DefaultSearchOptions().Search
+
+	hash, err := QueryHash(newPerson("clark@example.com", "lois@example.com"), params)
+	require.NoError(t, err)
+	assert.Len(t, hash, 64)
+
+	// Same terms, different order and case
+	same, err := QueryHash(newPerson("Lois@example.com", "clark@example.com"), params)
+	require.NoError(t, err)
+	assert.Equal(t, hash, same)
+
+	// The pretty flag does not change the results
+	params.Pretty = !params.Pretty
+	same, err = QueryHash(newPerson("clark@example.com", "lois@example.com"), params)
+	require.NoError(t, err)
+	assert.Equal(t, hash, same)
+
+	// Different terms or parameters
+	other, err := QueryHash(newPerson("clark@example.com"), params)
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, other)
+
+	params.HideSponsored = !params.HideSponsored
+	other, err = QueryHash(newPerson("clark@example.com", "lois@example.com"), params)
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, other)
+}
+
+// TestClient_SearchCanonicalQuery will test that the search sends the canonical query
+func TestClient_SearchCanonicalQuery(t *testing.T) {
+	t.Parallel()
+
+	mock := &searchResponse{}
+	c := NewClient(WithAPIKey(testKey), WithHTTPClient(mock))
+
+	person := NewPerson()
+	require.NoError(t, person.AddEmail(testEmail))
+	require.NoError(t, person.AddJob("Reporter", "Daily Planet", "", "", ""))
+	_, err := c.Search(context.Background(), person)
+	require.NoError(t, err)
+
+	expected, err := CanonicalQuery(person)
+	require.NoError(t, err)
+	form := mock.lastForm.Load().(url.Values) //nolint:forcetypeassert,errcheck // Only url.Values are stored
+	assert.Equal(t, string(expected), form.Get(fieldPerson))
+}
+
+// ExampleCanonicalQuery example using CanonicalQuery()
+func ExampleCanonicalQuery() {
+	person := NewPerson()
+	_ = person.AddEmail("clark@example.com")
+	query, _ := CanonicalQuery(person)
+	fmt.Println(string(query))
+	// Output:{"emails":[{"address":"clark@example.com"}]}
+}
+
+// BenchmarkQueryHash benchmarks the method QueryHash()
+func BenchmarkQueryHash(b *testing.B) {
+	person := NewPerson()
+	_ = person.AddEmail(testEmail)
+	_ = person.AddNameRaw("Clark Kent")
+	params := *DefaultSearchOptions().Search
+	for i := 0; i < b.N; i++ {
+		_, _ = QueryHash(person, params)
+	}
+}
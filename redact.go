@@ -0,0 +1,367 @@
+package pipl
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// RedactStrategy is how the values of a field are redacted (see RedactionPolicy)
+type RedactStrategy int
+
+const (
+	// RedactKeep keeps the values as they are
+	RedactKeep RedactStrategy = iota
+
+	// RedactDrop removes the values
+	RedactDrop
+
+	// RedactMask masks the values, for example "j***@gmail.com", "***-***-1234" or "J*** S***"
+	// (dates keep only the year)
+	RedactMask
+
+	// RedactHash replaces the values with a salted hash (HMAC-SHA256), to match values without showing them.
+	// Without a salt (RedactionPolicy.Salt) the values are dropped, as unsalted hashes are easy to reverse.
+	RedactHash
+
+	// RedactTruncate keeps only the first characters of the values (see RedactionPolicy.TruncateLength)
+	RedactTruncate
+)
+
+// DefaultTruncateLength is the number of characters kept by RedactTruncate, if not set in the policy
+const DefaultTruncateLength = 4
+
+// redactMask is the replacement of masked characters
+const redactMask = "***"
+
+// redactKind is how a single value is masked (and if it can be hashed or truncated)
+type redactKind int
+
+const (
+	redactText  redactKind = iota // First letter of each word, for example "J*** S***"
+	redactEmail                   // First letter of the local part, for example "j***@gmail.com"
+	redactTail                    // Last 4 letters or digits, for example "***-***-1234"
+	redactDate                    // Year only, for example "1986"
+	redactClear                   // Always removed (numbers, hashes and tokens)
+)
+
+// redactFields are the sensitive values of the person, relationship and source fields (by JSON name):
+// the Go field names (a dot for nested fields, empty for the field itself) and how they are masked.
+// Other fields (for example @match or @category) are never redacted.
+var redactFields = map[string]map[string]redactKind{ //nolint:gochecknoglobals // Lookup table
+	"@origin_url":      {"": redactText},
+	"@search_pointer":  {"": redactClear},
+	"addresses":        {"Apartment": redactText, "Display": redactText, "House": redactText, "POBox": redactText, "Raw": redactText, "Street": redactText, "ZipCode": redactText},
+	"dob":              {"DateRange.End": redactDate, "DateRange.Start": redactDate, "Display": redactText},
+	"educations":       {"Degree": redactText, "Display": redactText, "School": redactText},
+	"emails":           {"Address": redactEmail, "AddressMD5": redactClear},
+	"ethnicities":      {"Content": redactText},
+	"gender":           {"Content": redactText},
+	"images":           {"ThumbnailToken": redactClear, "ThumbnailURL": redactText, "URL": redactText},
+	"jobs":             {"Display": redactText, "Industry": redactText, "Organization": redactText, "Title": redactText},
+	"languages":        {"Display": redactText, "Language": redactText, "Region": redactText},
+	"names":            {"Display": redactText, "First": redactText, "Last": redactText, "Middle": redactText, "Raw": redactText},
+	"origin_countries": {"Country": redactText},
+	"phones":           {"Display": redactTail, "DisplayInternational": redactTail, "Extension": redactClear, "Number": redactClear, "Raw": redactTail},
+	"urls":             {"URL": redactText},
+	"user_ids":         {"Content": redactText},
+	"usernames":        {"Content": redactText},
+	"vehicles":         {"Display": redactText, "VIN": redactTail},
+}
+
+// RedactionPolicy is how the personal data is redacted (see Person.Redact), by field
+//
+// Fields are the JSON names, for example "emails", "phones", "dob" or "@search_pointer". The fields
+// of relationships use the same policy, and "relationships" itself can be kept, dropped or redacted.
+// Fields that are not in the JSON (for example, the unknown fields in Extra) are always removed,
+// as they cannot be redacted.
+//
+// DO NOT CHANGE ORDER - Optimized for memory (malign)
+type RedactionPolicy struct {
+	Salt           []byte                    // Salt (HMAC key) of RedactHash (required, or hashed fields are dropped)
+	Fields         map[string]RedactStrategy // Strategy by field
+	Default        RedactStrategy            // Strategy of the sensitive fields that are not in Fields
+	TruncateLength int                       // Characters kept by RedactTruncate (DefaultTruncateLength if 0)
+}
+
+// LogSafePolicy returns the built-in policy for logs: names, emails, phones, addresses and jobs
+// are masked, the date of birth keeps only the year, and everything else that is sensitive is removed
+func LogSafePolicy() *RedactionPolicy {
+	return &RedactionPolicy{
+		Default: RedactDrop,
+		Fields: map[string]RedactStrategy{
+			"addresses":        RedactMask,
+			"dob":              RedactMask,
+			"emails":           RedactMask,
+			"gender":           RedactKeep,
+			"jobs":             RedactMask,
+			"languages":        RedactKeep,
+			"names":            RedactMask,
+			"origin_countries": RedactKeep,
+			"phones":           RedactMask,
+			"relationships":    RedactDrop,
+		},
+	}
+}
+
+// SupportAgentPolicy returns the built-in policy for support agents: enough to confirm an identity
+// (names, masked emails and phones, the city and state of addresses, the birth year), without the
+// sensitive values (search pointers, user ids and ethnicities)
+func SupportAgentPolicy() *RedactionPolicy {
+	return &RedactionPolicy{
+		Default: RedactKeep,
+		Fields: map[string]RedactStrategy{
+			"@search_pointer": RedactDrop,
+			"addresses":       RedactMask,
+			"dob":             RedactMask,
+			"emails":          RedactMask,
+			"ethnicities":     RedactDrop,
+			"phones":          RedactMask,
+			"relationships":   RedactMask,
+			"user_ids":        RedactDrop,
+			"vehicles":        RedactMask,
+		},
+	}
+}
+
+// Redact returns a redacted (deep) copy of the person, the person is not changed. A nil policy
+// is the LogSafePolicy().
+func (p *Person) Redact(policy *RedactionPolicy) *Person {
+	redacted := deepCopy(p)
+	policy.orDefault().redactStruct(reflect.ValueOf(redacted).Elem())
+	return redacted
+}
+
+// Redact returns a redacted (deep) copy of the relationship, the relationship is not changed (a nil policy is the LogSafePolicy())
+func (r *Relationship) Redact(policy *RedactionPolicy) *Relationship {
+	redacted := deepCopy(r)
+	policy.orDefault().redactStruct(reflect.ValueOf(redacted).Elem())
+	return redacted
+}
+
+// Redact returns a redacted (deep) copy of the source, the source is not changed (a nil policy is the LogSafePolicy())
+func (s *Source) Redact(policy *RedactionPolicy) *Source {
+	redacted := deepCopy(s)
+	policy.orDefault().redactStruct(reflect.ValueOf(redacted).Elem())
+	return redacted
+}
+
+// Redact returns a redacted (deep) copy of the response (the query, the person, the possible persons
+// and the sources), the response is not changed. A nil policy is the LogSafePolicy().
+func (r *Response) Redact(policy *RedactionPolicy) *Response {
+	policy = policy.orDefault()
+	redacted := deepCopy(r)
+	redacted.Extra = nil
+	policy.redactStruct(reflect.ValueOf(&redacted.Query).Elem())
+	policy.redactStruct(reflect.ValueOf(&redacted.Person).Elem())
+	for index := range redacted.PossiblePersons {
+		policy.redactStruct(reflect.ValueOf(&redacted.PossiblePersons[index]).Elem())
+	}
+	for index := range redacted.Sources {
+		policy.redactStruct(reflect.ValueOf(&redacted.Sources[index]).Elem())
+	}
+	return redacted
+}
+
+// orDefault returns the policy, or the LogSafePolicy() if the policy is nil
+func (p *RedactionPolicy) orDefault() *RedactionPolicy {
+	if p == nil {
+		return LogSafePolicy()
+	}
+	return p
+}
+
+// strategy returns the strategy of the field (RedactHash without a salt is RedactDrop)
+func (p *RedactionPolicy) strategy(field string) RedactStrategy {
+	strategy, ok := p.Fields[field]
+	if !ok {
+		strategy = p.Default
+	}
+	if strategy == RedactHash && len(p.Salt) == 0 {
+		return RedactDrop
+	}
+	return strategy
+}
+
+// redactStruct redacts the fields of a person, relationship or source (in place)
+func (p *RedactionPolicy) redactStruct(value reflect.Value) {
+	for index := 0; index < value.NumField(); index++ {
+		field := value.Field(index)
+
+		// Fields that are not in the JSON (Extra, bookkeeping) cannot be redacted
+		name, _, _ := strings.Cut(value.Type().Field(index).Tag.Get("json"), ",")
+		if name == "-" {
+			field.Set(reflect.Zero(field.Type()))
+			continue
+		}
+
+		strategy := p.strategy(name)
+		if name == "relationships" {
+			switch strategy {
+			case RedactKeep:
+			case RedactDrop:
+				field.Set(reflect.Zero(field.Type()))
+			case RedactMask, RedactHash, RedactTruncate:
+				for item := 0; item < field.Len(); item++ {
+					p.redactStruct(field.Index(item))
+				}
+			}
+			continue
+		}
+
+		values, ok := redactFields[name]
+		if !ok || strategy == RedactKeep {
+			continue
+		}
+		if strategy == RedactDrop {
+			field.Set(reflect.Zero(field.Type()))
+			continue
+		}
+
+		switch field.Kind() { //nolint:exhaustive // Only field values are redacted
+		case reflect.Slice:
+			for item := 0; item < field.Len(); item++ {
+				p.redactValues(field.Index(item), values, strategy)
+			}
+		case reflect.Pointer:
+			if !field.IsNil() {
+				p.redactValues(field.Elem(), values, strategy)
+			}
+		case reflect.Struct, reflect.String:
+			p.redactValues(field, values, strategy)
+		}
+	}
+}
+
+// redactValues redacts the sensitive values of a field value (a struct, or a string)
+func (p *RedactionPolicy) redactValues(value reflect.Value, values map[string]redactKind, strategy RedactStrategy) {
+	for path, kind := range values {
+		field := value
+		if len(path) > 0 {
+			for _, name := range strings.Split(path, ".") {
+				field = field.FieldByName(name)
+			}
+		}
+
+		if field.Kind() != reflect.String || kind == redactClear {
+			field.Set(reflect.Zero(field.Type()))
+			continue
+		}
+		field.SetString(p.redactString(field.String(), kind, strategy))
+	}
+}
+
+// redactString redacts a single value
+func (p *RedactionPolicy) redactString(value string, kind redactKind, strategy RedactStrategy) string {
+	if len(value) == 0 {
+		return ""
+	}
+
+	switch strategy {
+	case RedactHash:
+		hash := hmac.New(sha256.New, p.Salt)
+		_, _ = hash.Write([]byte(value))
+		return hex.EncodeToString(hash.Sum(nil))
+	case RedactTruncate:
+		length := p.TruncateLength
+		if length <= 0 {
+			length = DefaultTruncateLength
+		}
+		if runes := []rune(value); len(runes) > length {
+			return string(runes[:length])
+		}
+		return value
+	case RedactKeep, RedactDrop, RedactMask:
+	}
+	return maskValue(value, kind)
+}
+
+// maskValue masks a single value (see RedactMask)
+func maskValue(value string, kind redactKind) string {
+	switch kind {
+	case redactEmail:
+		if local, domain, found := strings.Cut(value, "@"); found && len(local) > 0 {
+			return maskWords(local) + "@" + domain
+		}
+	case redactTail:
+		return maskTail(value, 4)
+	case redactDate:
+		if date, err := ParseDate(value); err == nil && !date.IsZero() {
+			return date.Time.Format("2006")
+		}
+		return redactMask
+	case redactText, redactClear:
+	}
+	return maskWords(value)
+}
+
+// maskWords keeps the first letter of each word, for example "Clark Kent" is "C*** K***"
+func maskWords(value string) string {
+	words := strings.Fields(value)
+	for index, word := range words {
+		first := []rune(word)[0]
+		words[index] = string(first) + redactMask
+	}
+	return strings.Join(words, " ")
+}
+
+// maskTail masks the letters and digits, except the last ones, for example "978-555-1234" is "***-***-1234"
+func maskTail(value string, keep int) string {
+	runes := []rune(value)
+	for index := len(runes) - 1; index >= 0; index-- {
+		if !unicode.IsLetter(runes[index]) && !unicode.IsDigit(runes[index]) {
+			continue
+		}
+		if keep > 0 {
+			keep--
+			continue
+		}
+		runes[index] = '*'
+	}
+	return string(runes)
+}
+
+// deepCopy returns a deep copy of the value (slices, maps and pointers are copied)
+func deepCopy[T any](value *T) *T {
+	copied := new(T)
+	copyValue(reflect.ValueOf(copied).Elem(), reflect.ValueOf(value).Elem())
+	return copied
+}
+
+// copyValue deep copies src into dst (of the same type)
+func copyValue(dst, src reflect.Value) {
+	switch src.Kind() { //nolint:exhaustive // Other kinds are copied by value
+	case reflect.Pointer:
+		if !src.IsNil() {
+			dst.Set(reflect.New(src.Elem().Type()))
+			copyValue(dst.Elem(), src.Elem())
+		}
+	case reflect.Slice:
+		if !src.IsNil() {
+			dst.Set(reflect.MakeSlice(src.Type(), src.Len(), src.Len()))
+			for index := 0; index < src.Len(); index++ {
+				copyValue(dst.Index(index), src.Index(index))
+			}
+		}
+	case reflect.Map:
+		if !src.IsNil() {
+			dst.Set(reflect.MakeMapWithSize(src.Type(), src.Len()))
+			for _, key := range src.MapKeys() {
+				item := reflect.New(src.Type().Elem()).Elem()
+				copyValue(item, src.MapIndex(key))
+				dst.SetMapIndex(key, item)
+			}
+		}
+	case reflect.Struct:
+		for index := 0; index < src.NumField(); index++ {
+			if dst.Field(index).CanSet() {
+				copyValue(dst.Field(index), src.Field(index))
+			}
+		}
+	default:
+		dst.Set(src)
+	}
+}
@@ -0,0 +1,224 @@
+package pipl
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRedactPerson returns a person with sensitive values
+func newTestRedactPerson() *Person {
+	return &Person{
+		ID:            "p1",
+		SearchPointer: testSearchPointer,
+		Match:         0.9,
+		Names:         []Name{{First: "Clark", Last: "Kent", Display: "Clark Kent"}},
+		Emails:        []Email{{Address: "john.doe@gmail.com", AddressMD5: "abc"}},
+		Phones:        []Phone{{CountryCode: 1, Number: 9785551234, Display: "978-555-1234", DisplayInternational: "+1 978-555-1234"}},
+		Addresses:     []Address{{House: "344", Street: "Clinton St", City: "Metropolis", State: "NY", Display: "344 Clinton St, Metropolis, NY"}},
+		DateOfBirth:   &DateOfBirth{DateRange: DateRange{Start: "1986-06-18", End: "1986-06-18"}, Display: "38 years old"},
+		Ethnicities:   []Ethnicity{{Content: "white"}},
+		UserIDs:       []UserID{{Content: "11231@facebook"}},
+		Relationships: []Relationship{{Names: []Name{{Display: "Lois Lane"}}, Emails: []Email{{Address: "lois@example.com"}}}},
+		Extra:         ExtraFields{"nickname": json.RawMessage(`"Smallville"`)},
+	}
+}
+
+// TestPerson_Redact will test redacting a person
+func TestPerson_Redact(t *testing.T) {
+	t.Parallel()
+
+	t.Run("mask", func(t *testing.T) {
+		person := newTestRedactPerson()
+		redacted := person.Redact(&RedactionPolicy{Default: RedactMask})
+
+		assert.Equal(t, "j***@gmail.com", redacted.Emails[0].Address)
+		assert.Empty(t, redacted.Emails[0].AddressMD5)
+		assert.Equal(t, "***-***-1234", redacted.Phones[0].Display)
+		assert.Equal(t, "+* ***-***-1234", redacted.Phones[0].DisplayInternational)
+		assert.Zero(t, redacted.Phones[0].Number)
+		assert.Equal(t, 1, redacted.Phones[0].CountryCode)
+		assert.Equal(t, "C*** K***", redacted.Names[0].Display)
+		assert.Equal(t, "C***", redacted.Names[0].First)
+		assert.Equal(t, "3***", redacted.Addresses[0].House)
+		assert.Equal(t, "Metropolis", redacted.Addresses[0].City) // Not sensitive
+		assert.Equal(t, "1986", redacted.DateOfBirth.DateRange.Start)
+		assert.Equal(t, "L*** L***", redacted.Relationships[0].Names[0].Display)
+		assert.Equal(t, "l***@example.com", redacted.Relationships[0].Emails[0].Address)
+		assert.Empty(t, redacted.SearchPointer)
+		assert.Nil(t, redacted.Extra)
+
+		// Not sensitive
+		assert.Equal(t, GUID("p1"), redacted.ID)
+		assert.InDelta(t, 0.9, redacted.Match, 0.001)
+	})
+
+	t.Run("original is not changed", func(t *testing.T) {
+		person := newTestRedactPerson()
+		original := newTestRedactPerson()
+		_ = person.Redact(&RedactionPolicy{Default: RedactDrop})
+		_ = person.Redact(&RedactionPolicy{Default: RedactMask})
+		assert.Equal(t, original, person)
+	})
+
+	t.Run("drop and keep", func(t *testing.T) {
+		redacted := newTestRedactPerson().Redact(&RedactionPolicy{
+			Default: RedactKeep,
+			Fields:  map[string]RedactStrategy{"emails": RedactDrop, "dob": RedactDrop, "relationships": RedactDrop},
+		})
+		assert.Nil(t, redacted.Emails)
+		assert.Nil(t, redacted.DateOfBirth)
+		assert.Nil(t, redacted.Relationships)
+		assert.Equal(t, "Clark Kent", redacted.Names[0].Display)
+		assert.Equal(t, testSearchPointer, redacted.SearchPointer)
+	})
+
+	t.Run("hash", func(t *testing.T) {
+		policy := &RedactionPolicy{Fields: map[string]RedactStrategy{"emails": RedactHash}, Salt: []byte("salt")}
+		first := newTestRedactPerson().Redact(policy)
+		second := newTestRedactPerson().Redact(policy)
+		assert.Len(t, first.Emails[0].Address, 64)
+		assert.Equal(t, first.Emails[0].Address, second.Emails[0].Address)
+
+		// Different salt, different hash
+		other := newTestRedactPerson().Redact(&RedactionPolicy{Fields: map[string]RedactStrategy{"emails": RedactHash}, Salt: []byte("pepper")})
+		assert.NotEqual(t, first.Emails[0].Address, other.Emails[0].Address)
+	})
+
+	t.Run("hash without a salt drops the values", func(t *testing.T) {
+		redacted := newTestRedactPerson().Redact(&RedactionPolicy{
+			Default: RedactKeep,
+			Fields:  map[string]RedactStrategy{"emails": RedactHash, "phones": RedactHash},
+		})
+		assert.Nil(t, redacted.Emails)
+		assert.Nil(t, redacted.Phones)
+		assert.Equal(t, "Clark Kent", redacted.Names[0].Display)
+
+		redacted = newTestRedactPerson().Redact(&RedactionPolicy{Default: RedactHash})
+		assert.Nil(t, redacted.Names)
+		assert.Nil(t, redacted.Relationships)
+	})
+
+	t.Run("nil policy", func(t *testing.T) {
+		var policy *RedactionPolicy
+		require.NotPanics(t, func() {
+			assert.Equal(t, newTestRedactPerson().Redact(LogSafePolicy()), newTestRedactPerson().Redact(policy))
+			assert.Equal(t, "L*** L***", newTestRedactPerson().Relationships[0].Redact(nil).Names[0].Display)
+			assert.Equal(t, "C*** K***", (&Source{Names: newTestRedactPerson().Names}).Redact(nil).Names[0].Display)
+			assert.Equal(t, "C*** K***", (&Response{Person: *newTestRedactPerson()}).Redact(nil).Person.Names[0].Display)
+		})
+	})
+
+	t.Run("truncate", func(t *testing.T) {
+		redacted := newTestRedactPerson().Redact(&RedactionPolicy{Fields: map[string]RedactStrategy{"names": RedactTruncate}})
+		assert.Equal(t, "Clar", redacted.Names[0].Display)
+		assert.Equal(t, "Kent", redacted.Names[0].Last)
+
+		redacted = newTestRedactPerson().Redact(&RedactionPolicy{Fields: map[string]RedactStrategy{"names": RedactTruncate}, TruncateLength: 1})
+		assert.Equal(t, "K", redacted.Names[0].Last)
+	})
+}
+
+// TestRedactionPolicies will test the built-in policies
+func TestRedactionPolicies(t *testing.T) {
+	t.Parallel()
+
+	t.Run("log safe", func(t *testing.T) {
+		redacted := newTestRedactPerson().Redact(LogSafePolicy())
+		assert.Equal(t, "j***@gmail.com", redacted.Emails[0].Address)
+		assert.Equal(t, "C*** K***", redacted.Names[0].Display)
+		assert.Equal(t, "1986", redacted.DateOfBirth.DateRange.Start)
+		assert.Nil(t, redacted.Ethnicities)
+		assert.Nil(t, redacted.UserIDs)
+		assert.Nil(t, redacted.Relationships)
+		assert.Empty(t, redacted.SearchPointer)
+	})
+
+	t.Run("log safe, merged person", func(t *testing.T) {
+		a := newTestRedactPerson()
+		b := &Person{
+			Emails:        []Email{{Address: "superman@example.com"}},
+			SearchPointer: "0123456789abcdef0123456789abcdef",
+			Extra:         ExtraFields{"@risk_score": json.RawMessage(`0.4`)},
+		}
+		merged, _ := MergePersons(a, b)
+
+		redacted := merged.Redact(LogSafePolicy())
+		assert.Empty(t, redacted.SearchPointer)
+		assert.Nil(t, redacted.Extra)
+		assert.Equal(t, "s***@example.com", redacted.Emails[1].Address)
+
+		data, err := json.Marshal(redacted)
+		require.NoError(t, err)
+		assert.NotContains(t, string(data), a.SearchPointer)
+		assert.NotContains(t, string(data), b.SearchPointer)
+		assert.NotContains(t, string(data), "superman")
+	})
+
+	t.Run("support agent", func(t *testing.T) {
+		redacted := newTestRedactPerson().Redact(SupportAgentPolicy())
+		assert.Equal(t, "Clark Kent", redacted.Names[0].Display)
+		assert.Equal(t, "j***@gmail.com", redacted.Emails[0].Address)
+		assert.Equal(t, "***-***-1234", redacted.Phones[0].Display)
+		assert.Equal(t, "Metropolis", redacted.Addresses[0].City)
+		assert.Nil(t, redacted.Ethnicities)
+		assert.Nil(t, redacted.UserIDs)
+		assert.Equal(t, "Lois Lane", redacted.Relationships[0].Names[0].Display)
+		assert.Equal(t, "l***@example.com", redacted.Relationships[0].Emails[0].Address)
+	})
+}
+
+// TestResponse_Redact will test redacting a response, its sources and relationships
+func TestResponse_Redact(t *testing.T) {
+	t.Parallel()
+
+	response, err := loadResponseData("response_schema.json")
+	require.NoError(t, err)
+	original, err := json.Marshal(response)
+	require.NoError(t, err)
+
+	redacted := response.Redact(LogSafePolicy())
+	assert.Equal(t, "c***@example.com", redacted.Person.Emails[0].Address)
+	require.NotEmpty(t, redacted.Sources)
+	for _, source := range redacted.Sources {
+		assert.Empty(t, source.OriginURL)
+		assert.Empty(t, source.Images)
+		assert.Empty(t, source.Vehicles)
+		assert.NotEmpty(t, source.Category) // Not sensitive
+	}
+
+	// The original is not changed
+	after, err := json.Marshal(response)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(original), string(after))
+
+	// Single relationship and source
+	relationship := response.Person.Relationships[0].Redact(&RedactionPolicy{Default: RedactDrop})
+	assert.Empty(t, relationship.Names)
+	assert.Equal(t, response.Person.Relationships[0].Type, relationship.Type)
+
+	source := response.Sources[0].Redact(SupportAgentPolicy())
+	assert.Equal(t, response.Sources[0].OriginURL, source.OriginURL)
+}
+
+// ExamplePerson_Redact example using Redact()
+func ExamplePerson_Redact() {
+	person := NewPerson()
+	_ = person.AddEmail("john.doe@gmail.com")
+	_ = person.AddPhoneRaw("978-555-1234")
+	redacted := person.Redact(LogSafePolicy())
+	fmt.Println(redacted.Emails[0].Address, redacted.Phones[0].Raw)
+	// Output:j***@gmail.com +*******1234
+}
+
+// BenchmarkPerson_Redact benchmarks the method Redact()
+func BenchmarkPerson_Redact(b *testing.B) {
+	person := newTestRedactPerson()
+	policy := LogSafePolicy()
+	for i := 0; i < b.N; i++ {
+		_ = person.Redact(policy)
+	}
+}
@@ -2,7 +2,8 @@ package pipl
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/url"
@@ -11,6 +12,9 @@ import (
 
 // httpRequest is a generic pipl request wrapper that can be used without the constraints
 // of the Search or SearchByPointer methods
+//
+// An API error (the error field of the response) is returned as ErrAPIResponse. With
+// DecodeModeStrict, the response is returned along with the *UnknownFieldsError.
 func httpRequest(ctx context.Context, client *Client, endpoint string,
 	params *url.Values,
 ) (response *Response, err error) {
@@ -47,10 +51,17 @@ func httpRequest(ctx context.Context, client *Client, endpoint string,
 		return nil, err
 	}
 
-	// Parse the response
+	// Parse the response (unknown fields are reported after the API error)
 	response = new(Response)
-	if err = json.Unmarshal(body, response); err != nil {
-		return nil, err
+	var decodeErr error
+	if decodeErr = Decode(body, response, client.options.decodeMode); decodeErr != nil &&
+		!errors.Is(decodeErr, ErrUnknownFields) {
+		return nil, decodeErr
+	}
+
+	// Error from the API
+	if len(response.Error) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrAPIResponse, response.Error)
 	}
 
 	// Thumbnail generation enabled?
@@ -67,5 +78,11 @@ func httpRequest(ctx context.Context, client *Client, endpoint string,
 		}
 	}
 
-	return response, nil
+	return response, decodeErr
+}
+
+// searchFailed returns true if the search failed: with DecodeModeStrict, an *UnknownFieldsError
+// that comes with the response is not a failure (the response can still be used)
+func searchFailed(response *Response, err error) bool {
+	return err != nil && (response == nil || !errors.Is(err, ErrUnknownFields))
 }
@@ -115,7 +115,7 @@ func Test_GoodResponse(t *testing.T) {
 	require.Equal(t, len(response.Person.Emails), response.AvailableData.Premium.Emails)
 
 	// Test email 1
-	require.Equal(t, "work", response.Person.Emails[0].Type)
+	require.Equal(t, EmailTypeWork, response.Person.Emails[0].Type)
 	require.False(t, response.Person.Emails[0].EmailProvider)
 	require.Equal(t, "clark.kent@thedailyplanet.com", response.Person.Emails[0].Address)
 	require.Equal(t, fmt.Sprintf("%x", md5.Sum([]byte(response.Person.Emails[0].Address))), response.Person.Emails[0].AddressMD5) //nolint:gosec // Testing PIPL API MD5 behavior
@@ -127,7 +127,7 @@ func Test_GoodResponse(t *testing.T) {
 	require.Equal(t, fmt.Sprintf("%x", md5.Sum([]byte(response.Person.Emails[1].Address))), response.Person.Emails[1].AddressMD5) //nolint:gosec // Testing PIPL API MD5 behavior
 
 	// Test email 3
-	require.Equal(t, "personal", response.Person.Emails[2].Type)
+	require.Equal(t, EmailTypePersonal, response.Person.Emails[2].Type)
 	require.True(t, response.Person.Emails[2].EmailProvider)
 	require.Equal(t, "clark@gmail.com", response.Person.Emails[2].Address)
 	require.Equal(t, fmt.Sprintf("%x", md5.Sum([]byte(response.Person.Emails[2].Address))), response.Person.Emails[2].AddressMD5) //nolint:gosec // Testing PIPL API MD5 behavior
@@ -155,7 +155,7 @@ func Test_GoodResponse(t *testing.T) {
 	require.Equal(t, len(response.Person.Phones), response.AvailableData.Premium.Phones)
 
 	// Test phone 1
-	require.Equal(t, "home_phone", response.Person.Phones[0].Type)
+	require.Equal(t, PhoneTypeHomePhone, response.Person.Phones[0].Type)
 	require.Equal(t, 1, response.Person.Phones[0].CountryCode)
 	require.Equal(t, int64(9785550145), response.Person.Phones[0].Number)
 	require.Equal(t, "978-555-0145", response.Person.Phones[0].Display)
@@ -208,7 +208,7 @@ func Test_GoodResponse(t *testing.T) {
 
 	// Test address 1
 	require.Equal(t, "2005-02-12", response.Person.Addresses[0].ValidSince)
-	require.Equal(t, "work", response.Person.Addresses[0].Type)
+	require.Equal(t, AddressTypeWork, response.Person.Addresses[0].Type)
 	require.Equal(t, DefaultCountry, response.Person.Addresses[0].Country)
 	require.Equal(t, testState, response.Person.Addresses[0].State)
 	require.Equal(t, "Metropolis", response.Person.Addresses[0].City)
@@ -219,7 +219,7 @@ func Test_GoodResponse(t *testing.T) {
 
 	// Test address 2
 	require.Equal(t, "1999-02-01", response.Person.Addresses[1].ValidSince)
-	require.Equal(t, "home", response.Person.Addresses[1].Type)
+	require.Equal(t, AddressTypeHome, response.Person.Addresses[1].Type)
 	require.Equal(t, DefaultCountry, response.Person.Addresses[1].Country)
 	require.Equal(t, testState, response.Person.Addresses[1].State)
 	require.Equal(t, testCity, response.Person.Addresses[1].City)
@@ -305,19 +305,19 @@ func Test_GoodResponse(t *testing.T) {
 	require.Equal(t, "edc6aa8fa3f211cfad7c12a0ba5b32f4", response.Person.URLs[0].SourceID)
 	require.Equal(t, "linkedin.com", response.Person.URLs[0].Domain)
 	require.Equal(t, "LinkedIn", response.Person.URLs[0].Name)
-	require.Equal(t, "professional_and_business", response.Person.URLs[0].Category)
+	require.Equal(t, SourceCategoryProfessionalAndBusiness, response.Person.URLs[0].Category)
 	require.Equal(t, "https://linkedin.com/clark.kent", response.Person.URLs[0].URL)
 
 	// Test url #2
 	require.Equal(t, "5d836a4acc55922e49fc709c7a39e233", response.Person.URLs[1].SourceID)
 	require.Equal(t, "facebook.com", response.Person.URLs[1].Domain)
 	require.Equal(t, "Facebook", response.Person.URLs[1].Name)
-	require.Equal(t, "personal_profiles", response.Person.URLs[1].Category)
+	require.Equal(t, SourceCategoryPersonalProfiles, response.Person.URLs[1].Category)
 	require.Equal(t, "https://facebook.com/superman", response.Person.URLs[1].URL)
 
 	// Test url #3
 	require.Equal(t, "linkedin.com", response.Person.URLs[2].Domain)
-	require.Equal(t, "professional_and_business", response.Person.URLs[2].Category)
+	require.Equal(t, SourceCategoryProfessionalAndBusiness, response.Person.URLs[2].Category)
 	require.Equal(t, "https://www.linkedin.com/pub/superman/20/7a/365", response.Person.URLs[2].URL)
 }
 
@@ -356,3 +356,75 @@ func Test_PackageErrorResponse(t *testing.T) {
 	require.Equal(t, http.StatusBadRequest, response.HTTPStatusCode)
 	require.Equal(t, "Your data package does not contain email", response.Error)
 }
+
+// Test_SchemaResponse test a response JSON with vehicles, relationship urls, source images and the newer flags
+func Test_SchemaResponse(t *testing.T) {
+	// Load the response data
+	response, err := loadResponseData("response_schema.json")
+	require.NoError(t, err)
+
+	require.Equal(t, 1, response.AvailableData.Premium.Vehicles)
+
+	// Email and phone flags
+	require.True(t, response.Person.Emails[0].Deliverable)
+	require.True(t, response.Person.Emails[0].EmailProvider)
+	require.True(t, response.Person.Phones[0].DoNotCall)
+	require.True(t, response.Person.Phones[0].VoIP)
+
+	// Relationship search pointer and urls
+	require.Equal(t, "2906090343183157724859920008073008866", response.Person.Relationships[0].SearchPointer)
+	require.Equal(t, "Cousin", response.Person.Relationships[0].Subtype)
+	require.Len(t, response.Person.Relationships[0].URLs, 1)
+	require.Equal(t, SourceCategoryPersonalProfiles, response.Person.Relationships[0].URLs[0].Category)
+	require.Equal(t, "https://example.com/kara", response.Person.Relationships[0].URLs[0].URL)
+
+	// Vehicles
+	require.Len(t, response.Person.Vehicles, 1)
+	require.Equal(t, "2G1FB1E39F9000000", response.Person.Vehicles[0].VIN)
+	require.Equal(t, 2015, response.Person.Vehicles[0].Year)
+	require.Equal(t, "Chevrolet", response.Person.Vehicles[0].Make)
+	require.Equal(t, "Camaro", response.Person.Vehicles[0].Model)
+	require.Equal(t, "Red", response.Person.Vehicles[0].Color)
+	require.Equal(t, "Car", response.Person.Vehicles[0].Type)
+	require.Equal(t, "2015-03-01", response.Person.Vehicles[0].ValidSince)
+	validSince, err := response.Person.Vehicles[0].ValidSinceDate()
+	require.NoError(t, err)
+	require.Equal(t, DatePrecisionDay, validSince.Precision)
+
+	// Source images and vehicles
+	require.Len(t, response.Sources, 2)
+	require.Len(t, response.Sources[0].Images, 1)
+	require.Equal(t, "https://example.com/clark.jpg", response.Sources[0].Images[0].URL)
+	require.NotEmpty(t, response.Sources[0].Images[0].ThumbnailToken)
+	require.Len(t, response.Sources[1].Vehicles, 1)
+	require.Equal(t, "2G1FB1E39F9000000", response.Sources[1].Vehicles[0].VIN)
+}
+
+// Test_ResponseRoundTrip test that every response JSON survives encoding and decoding without losing data
+func Test_ResponseRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	files := []string{
+		"response_bad_key.json",
+		"response_not_found.json",
+		"response_package_error.json",
+		"response_possible_persons.json",
+		"response_schema.json",
+		"response_success.json",
+	}
+
+	for _, file := range files {
+		t.Run(file, func(t *testing.T) {
+			response, err := loadResponseData(file)
+			require.NoError(t, err)
+
+			var data []byte
+			data, err = json.Marshal(response)
+			require.NoError(t, err)
+
+			decoded := new(Response)
+			require.NoError(t, json.Unmarshal(data, decoded))
+			require.Equal(t, response, decoded)
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package pipl
+
+import (
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// SourceFilter is the client-side filter of the response sources (see Response.FilterSources)
+//
+// Sponsored and premium sources can then be fetched (see SearchParameters.HideSponsored) but
+// excluded from display. Empty lists do not filter.
+//
+// DO NOT CHANGE ORDER - Optimized for memory (malign)
+type SourceFilter struct {
+	Categories        []SourceCategory // Only keep sources in these categories (allow list)
+	Domains           []string         // Only keep sources from these domains or their subdomains (allow list)
+	ExcludeCategories []SourceCategory // Remove sources in these categories (deny list)
+	ExcludeDomains    []string         // Remove sources from these domains or their subdomains (deny list)
+	MinimumMatch      float32          // Remove sources with a lower @match
+	ExcludePremium    bool             // Remove @premium sources
+	ExcludeSponsored  bool             // Remove @sponsored sources
+	Recompute         bool             // Remove person values that were only asserted by removed sources
+}
+
+// SourceFilterReport is the report of the sources (and person values) removed by FilterSources
+type SourceFilterReport struct {
+	RemovedSources []Source           `json:"removed_sources"`
+	RemovedValues  []*ValueProvenance `json:"removed_values"` // The removed values, with the sources that asserted them
+}
+
+// Keep returns true if the source passes the filter
+func (f *SourceFilter) Keep(source *Source) bool {
+	switch {
+	case f.ExcludeSponsored && source.Sponsored,
+		f.ExcludePremium && source.Premium,
+		source.Match < f.MinimumMatch,
+		len(f.Categories) > 0 && !slices.Contains(f.Categories, source.Category),
+		slices.Contains(f.ExcludeCategories, source.Category),
+		len(f.Domains) > 0 && !matchesDomain(f.Domains, source.Domain),
+		matchesDomain(f.ExcludeDomains, source.Domain):
+		return false
+	}
+	return true
+}
+
+// FilterSources will remove (in place) the sources that do not pass the filter, and report the removed sources.
+//
+// With Recompute, the person and possible person values that were asserted by sources, but only by
+// removed sources, are also removed (see Response.Provenance). Values without any source are kept.
+func (r *Response) FilterSources(filter SourceFilter) SourceFilterReport {
+	report := SourceFilterReport{RemovedSources: []Source{}, RemovedValues: []*ValueProvenance{}}
+	if len(r.Sources) == 0 {
+		return report
+	}
+
+	kept := make([]Source, 0, len(r.Sources))
+	for index := range r.Sources {
+		if filter.Keep(&r.Sources[index]) {
+			kept = append(kept, r.Sources[index])
+		} else {
+			report.RemovedSources = append(report.RemovedSources, r.Sources[index])
+		}
+	}
+
+	if filter.Recompute && len(report.RemovedSources) > 0 {
+		report.RemovedValues = append(report.RemovedValues, recomputePerson(provenancePerson, &r.Person, r.Sources, kept)...)
+		for index := range r.PossiblePersons {
+			report.RemovedValues = append(report.RemovedValues, recomputePerson(possiblePersonPosition(index), &r.PossiblePersons[index], r.Sources, kept)...)
+		}
+	}
+
+	r.Sources = kept
+	return report
+}
+
+// PremiumSources returns the @premium sources of the response
+func (r *Response) PremiumSources() []Source {
+	return r.sourcesWhere(func(source *Source) bool { return source.Premium })
+}
+
+// SponsoredSources returns the @sponsored sources of the response
+func (r *Response) SponsoredSources() []Source {
+	return r.sourcesWhere(func(source *Source) bool { return source.Sponsored })
+}
+
+// sourcesWhere returns the sources matching the condition
+func (r *Response) sourcesWhere(condition func(*Source) bool) []Source {
+	sources := []Source{}
+	for index := range r.Sources {
+		if condition(&r.Sources[index]) {
+			sources = append(sources, r.Sources[index])
+		}
+	}
+	return sources
+}
+
+// recomputePerson removes the values of the person (at the position) that lost all their sources, and returns their provenance
+func recomputePerson(position string, person *Person, before, after []Source) []*ValueProvenance {
+	oldProvenance := personProvenance(position, person, before)
+	newProvenance := personProvenance(position, person, after)
+
+	var removed []*ValueProvenance
+	lost := func(path string) bool {
+		old := oldProvenance.At(position, path)
+		if old == nil || old.Count == 0 || newProvenance.At(position, path).Count > 0 {
+			return false
+		}
+		removed = append(removed, old)
+		return true
+	}
+
+	value := reflect.ValueOf(person).Elem()
+	for index := 0; index < value.NumField(); index++ {
+		field := value.Field(index)
+		name, _, _ := strings.Cut(value.Type().Field(index).Tag.Get("json"), ",")
+
+		switch field.Kind() { //nolint:exhaustive // Only field values are recomputed
+		case reflect.Slice:
+			if field.Type().Elem().Kind() != reflect.Struct || field.Len() == 0 {
+				continue
+			}
+			keep := reflect.MakeSlice(field.Type(), 0, field.Len())
+			for item := 0; item < field.Len(); item++ {
+				if !lost(name + "[" + strconv.Itoa(item) + "]") {
+					keep = reflect.Append(keep, field.Index(item))
+				}
+			}
+			field.Set(keep)
+		case reflect.Pointer:
+			if !field.IsNil() && lost(name) {
+				field.Set(reflect.Zero(field.Type()))
+			}
+		}
+	}
+
+	// Duplicate values share the provenance
+	seen := make(map[*ValueProvenance]bool)
+	unique := removed[:0]
+	for _, provenance := range removed {
+		if !seen[provenance] {
+			seen[provenance] = true
+			unique = append(unique, provenance)
+		}
+	}
+	return unique
+}
+
+// personProvenance builds the provenance index of a single person (at the position)
+func personProvenance(position string, person *Person, sources []Source) *Provenance {
+	p := newProvenance()
+	p.addPerson(position, person, sources)
+	return p
+}
+
+// matchesDomain returns true if the domain is (a subdomain of) a domain in the list
+func matchesDomain(domains []string, domain string) bool {
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+	for _, d := range domains {
+		d = strings.ToLower(strings.TrimSuffix(d, "."))
+		if len(d) > 0 && (domain == d || strings.HasSuffix(domain, "."+d)) {
+			return true
+		}
+	}
+	return false
+}
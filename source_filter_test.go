@@ -0,0 +1,114 @@
+package pipl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSourceFilter_Keep will test the source filter conditions
+func TestSourceFilter_Keep(t *testing.T) {
+	t.Parallel()
+
+	source := &Source{
+		Category: SourceCategoryPersonalProfiles, Domain: "profiles.example.com",
+		Match: 0.8, Premium: true, Sponsored: true,
+	}
+
+	tests := []struct {
+		name   string
+		filter SourceFilter
+		keep   bool
+	}{
+		{"empty filter", SourceFilter{}, true},
+		{"exclude sponsored", SourceFilter{ExcludeSponsored: true}, false},
+		{"exclude premium", SourceFilter{ExcludePremium: true}, false},
+		{"minimum match below", SourceFilter{MinimumMatch: 0.8}, true},
+		{"minimum match above", SourceFilter{MinimumMatch: 0.9}, false},
+		{"allowed category", SourceFilter{Categories: []SourceCategory{SourceCategoryPersonalProfiles}}, true},
+		{"not allowed category", SourceFilter{Categories: []SourceCategory{SourceCategoryWebPages}}, false},
+		{"denied category", SourceFilter{ExcludeCategories: []SourceCategory{SourceCategoryPersonalProfiles}}, false},
+		{"allowed parent domain", SourceFilter{Domains: []string{"Example.com"}}, true},
+		{"not allowed domain", SourceFilter{Domains: []string{"ample.com"}}, false},
+		{"denied domain", SourceFilter{ExcludeDomains: []string{"profiles.example.com"}}, false},
+		{"other denied domain", SourceFilter{ExcludeDomains: []string{"example.org"}}, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.keep, test.filter.Keep(source))
+		})
+	}
+}
+
+// TestResponse_FilterSources will test filtering the sources of a response
+func TestResponse_FilterSources(t *testing.T) {
+	t.Parallel()
+
+	t.Run("remove sponsored sources", func(t *testing.T) {
+		response := newTestProvenanceResponse()
+		report := response.FilterSources(SourceFilter{ExcludeSponsored: true})
+
+		require.Len(t, report.RemovedSources, 1)
+		assert.Equal(t, "s3", report.RemovedSources[0].ID)
+		assert.Empty(t, report.RemovedValues)
+		assert.Len(t, response.Sources, 3)
+		assert.Len(t, response.Person.Emails, 3) // Not recomputed
+	})
+
+	t.Run("recompute the person", func(t *testing.T) {
+		response := newTestProvenanceResponse()
+		report := response.FilterSources(SourceFilter{ExcludePremium: true, Recompute: true})
+
+		require.Len(t, report.RemovedSources, 1)
+		assert.Equal(t, "s2", report.RemovedSources[0].ID)
+
+		// The phone was only asserted by the premium source
+		require.Len(t, report.RemovedValues, 1)
+		assert.Equal(t, "phones[0]", report.RemovedValues[0].Path)
+		assert.Equal(t, []string{"s2"}, report.RemovedValues[0].SourceIDs)
+		assert.Empty(t, response.Person.Phones)
+
+		// Emails still have sources (or never had any)
+		assert.Len(t, response.Person.Emails, 3)
+		assert.NotNil(t, response.Person.Gender)
+	})
+
+	t.Run("recompute single values and possible persons", func(t *testing.T) {
+		response := newTestProvenanceResponse()
+		report := response.FilterSources(SourceFilter{
+			Categories: []SourceCategory{SourceCategoryBackgroundReports},
+			Recompute:  true,
+		})
+
+		assert.Len(t, report.RemovedSources, 3)
+		assert.Nil(t, response.Person.Gender)
+		assert.Len(t, response.Person.Emails, 3)
+		assert.Empty(t, response.PossiblePersons[0].Emails)
+
+		var paths []string
+		for _, removed := range report.RemovedValues {
+			paths = append(paths, string(removed.PersonID)+":"+removed.Path)
+		}
+		assert.Equal(t, []string{"p1:gender", "p2:emails[0]"}, paths)
+	})
+
+	t.Run("no sources", func(t *testing.T) {
+		response := &Response{Person: Person{Emails: []Email{{Address: "clark@example.com"}}}}
+		report := response.FilterSources(SourceFilter{ExcludeSponsored: true, Recompute: true})
+		assert.Empty(t, report.RemovedSources)
+		assert.Len(t, response.Person.Emails, 1)
+	})
+}
+
+// TestResponse_PremiumSources will test the premium and sponsored source accessors
+func TestResponse_PremiumSources(t *testing.T) {
+	t.Parallel()
+
+	response := newTestProvenanceResponse()
+	require.Len(t, response.PremiumSources(), 1)
+	assert.Equal(t, "s2", response.PremiumSources()[0].ID)
+	require.Len(t, response.SponsoredSources(), 1)
+	assert.Equal(t, "s3", response.SponsoredSources()[0].ID)
+	assert.Empty(t, (&Response{}).PremiumSources())
+}
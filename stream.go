@@ -0,0 +1,149 @@
+package pipl
+
+import (
+	"context"
+	"iter"
+	"sync"
+	"sync/atomic"
+)
+
+// StreamOptions are the settings for streaming a batch of searches
+//
+// DO NOT CHANGE ORDER - Optimized for memory (malign)
+type StreamOptions struct {
+	// Batch are the options for the searches (concurrency, timeouts, rate limits, fail-fast and progress)
+	Batch BatchOptions
+
+	// Ordered yields the results in input order (default: as they complete)
+	Ordered bool
+}
+
+// SearchStream runs Search() on the search service (client) for every person produced by the people iterator, using
+// a bounded pool of workers. People are consumed lazily (only when a worker is ready)
+// and results are yielded as soon as they complete, so the full batch is never held in memory.
+//
+// The BatchResult is yielded with its error (nil if successful). Set StreamOptions.Ordered
+// to yield the results in the same order as the people were consumed. Breaking out of
+// the loop, an error in FailFast mode, or canceling the context will stop the workers;
+// searches that are in flight when the context is canceled are yielded with the context error.
+func SearchStream(ctx context.Context, service SearchService, people iter.Seq[*Person],
+	streamOptions StreamOptions,
+) iter.Seq2[BatchResult, error] {
+	options := streamOptions.Batch
+	return func(yield func(BatchResult, error) bool) {
+		streamCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		var (
+			consumed atomic.Int64
+			wg       sync.WaitGroup
+		)
+
+		workers := options.Concurrency
+		if workers <= 0 {
+			workers = DefaultBatchConcurrency
+		}
+		limiter := newRateLimiter(options.RequestsPerSecond)
+		jobs := make(chan BatchResult)
+		results := make(chan BatchResult)
+
+		// window limits the number of results in flight (or waiting to be yielded in order)
+		window := make(chan struct{}, workers*2)
+
+		// Consume the people (lazily)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer close(jobs)
+			for person := range people {
+				index := int(consumed.Add(1)) - 1
+				select {
+				case window <- struct{}{}:
+				case <-streamCtx.Done():
+					return
+				}
+				select {
+				case jobs <- BatchResult{Index: index, Person: person}:
+				case <-streamCtx.Done():
+					return
+				}
+			}
+		}()
+
+		// Start the workers (results are always sent, the consumer drains them)
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for job := range jobs {
+					job.Response, job.Err = batchSearchItem(streamCtx, service, limiter, job.Person, options.ItemTimeout)
+					results <- job
+				}
+			}()
+		}
+
+		// Close the results once everything has stopped
+		go func() {
+			wg.Wait()
+			close(results)
+		}()
+
+		// Drain any remaining results (on early exit) so the workers can stop
+		defer func() {
+			cancel()
+			for range results {
+			}
+		}()
+
+		progress := BatchProgress{}
+		emit := func(result BatchResult) bool {
+			<-window
+
+			progress.Completed++
+			progress.Index = result.Index
+			progress.Total = int(consumed.Load())
+			failed := searchFailed(result.Response, result.Err)
+			if failed {
+				progress.Failed++
+			} else {
+				progress.Succeeded++
+			}
+			if options.OnProgress != nil {
+				options.OnProgress(progress)
+			}
+
+			if !yield(result, result.Err) {
+				return false
+			}
+			return !failed || !options.FailFast
+		}
+
+		// Yield as they complete
+		if !streamOptions.Ordered {
+			for result := range results {
+				if !emit(result) {
+					return
+				}
+			}
+			return
+		}
+
+		// Yield in the order they were consumed
+		next := 0
+		pending := make(map[int]BatchResult)
+		for result := range results {
+			pending[result.Index] = result
+			for {
+				ready, ok := pending[next]
+				if !ok {
+					break
+				}
+				delete(pending, next)
+				next++
+				if !emit(ready) {
+					return
+				}
+			}
+		}
+	}
+}
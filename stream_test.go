@@ -0,0 +1,123 @@
+package pipl
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingPeople returns an iterator over the people, counting how many were consumed
+func countingPeople(people []*Person, consumed *int) iter.Seq[*Person] {
+	return func(yield func(*Person) bool) {
+		for _, person := range people {
+			*consumed++
+			if !yield(person) {
+				return
+			}
+		}
+	}
+}
+
+// TestSearchStream will test the method SearchStream()
+func TestSearchStream(t *testing.T) {
+	t.Parallel()
+
+	t.Run("unordered, all results", func(t *testing.T) {
+		c := NewClient(WithAPIKey(testKey), WithHTTPClient(&searchResponse{}))
+		people := newTestPeople(t, "one@example.com", "two@"+testFailDomain, "three@example.com")
+
+		var indexes []int
+		var failed int
+		for result, err := range SearchStream(context.Background(), c, slices.Values(people), StreamOptions{}) {
+			indexes = append(indexes, result.Index)
+			if err != nil {
+				failed++
+				require.ErrorIs(t, err, ErrAPIResponse)
+				continue
+			}
+			require.NotNil(t, result.Response)
+			assert.Same(t, people[result.Index], result.Person)
+		}
+		slices.Sort(indexes)
+		assert.Equal(t, []int{0, 1, 2}, indexes)
+		assert.Equal(t, 1, failed)
+	})
+
+	t.Run("ordered", func(t *testing.T) {
+		c := NewClient(WithAPIKey(testKey), WithHTTPClient(&searchResponse{}))
+		emails := make([]string, 0, 25)
+		for i := 0; i < 25; i++ {
+			emails = append(emails, fmt.Sprintf("person%d@example.com", i))
+		}
+
+		var progress BatchProgress
+		next := 0
+		for result, err := range SearchStream(context.Background(), c, slices.Values(newTestPeople(t, emails...)), StreamOptions{
+			Batch: BatchOptions{
+				Concurrency: 5,
+				OnProgress: func(p BatchProgress) {
+					progress = p
+				},
+			},
+			Ordered: true,
+		}) {
+			require.NoError(t, err)
+			require.Equal(t, next, result.Index)
+			next++
+		}
+		assert.Equal(t, len(emails), next)
+		assert.Equal(t, BatchProgress{Completed: 25, Index: 24, Succeeded: 25, Total: 25}, progress)
+	})
+
+	t.Run("break early consumes lazily", func(t *testing.T) {
+		mock := &searchResponse{}
+		c := NewClient(WithAPIKey(testKey), WithHTTPClient(mock))
+		emails := make([]string, 0, 100)
+		for i := 0; i < 100; i++ {
+			emails = append(emails, fmt.Sprintf("person%d@example.com", i))
+		}
+
+		consumed := 0
+		for _, err := range SearchStream(context.Background(), c, countingPeople(newTestPeople(t, emails...), &consumed), StreamOptions{
+			Batch: BatchOptions{Concurrency: 2},
+		}) {
+			require.NoError(t, err)
+			break
+		}
+		assert.Less(t, consumed, 100)
+		assert.Less(t, mock.calls.Load(), int32(100))
+	})
+
+	t.Run("fail-fast", func(t *testing.T) {
+		c := NewClient(WithAPIKey(testKey), WithHTTPClient(&searchResponse{}))
+		people := newTestPeople(t, "one@"+testFailDomain, "two@example.com", "three@example.com")
+
+		var yielded int
+		for _, err := range SearchStream(context.Background(), c, slices.Values(people), StreamOptions{
+			Batch:   BatchOptions{Concurrency: 1, FailFast: true},
+			Ordered: true,
+		}) {
+			yielded++
+			require.ErrorIs(t, err, ErrAPIResponse)
+		}
+		assert.Equal(t, 1, yielded)
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		mock := &searchResponse{}
+		c := NewClient(WithAPIKey(testKey), WithHTTPClient(mock))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		for _, err := range SearchStream(ctx, c, slices.Values(newTestPeople(t, "one@example.com")), StreamOptions{}) {
+			require.ErrorIs(t, err, context.Canceled)
+		}
+		assert.Equal(t, int32(0), mock.calls.Load())
+	})
+}
@@ -0,0 +1,564 @@
+package pipl
+
+import (
+	"bytes"
+	"cmp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// vCard constants (RFC 6350)
+const (
+	vCardBirthdayLayout = "20060102" // BDAY (basic format)
+	vCardLineLength     = 75         // Maximum octets per line, before folding
+	vCardNewLine        = "\r\n"
+	vCardVersion        = "4.0"
+)
+
+// vCardPhoneTypes are the TEL types, by phone @type
+var vCardPhoneTypes = map[PhoneType]string{ //nolint:gochecknoglobals // Lookup table
+	PhoneTypeHomeFax:   "fax,home",
+	PhoneTypeHomePhone: "voice,home",
+	PhoneTypeMobile:    "cell",
+	PhoneTypePager:     "pager",
+	PhoneTypeWorkFax:   "fax,work",
+	PhoneTypeWorkPhone: "voice,work",
+}
+
+// vCardProperty is a single (unfolded) content line of a vCard
+type vCardProperty struct {
+	params map[string][]string // Parameter values, by upper case name
+	group  string
+	name   string // Upper case name, for example "EMAIL"
+	value  string // Raw (escaped) value
+}
+
+// VCard will encode the person as a vCard 4.0 (RFC 6350), for importing as a contact.
+//
+// Names (FN and N from the primary name), emails, phones (with type), addresses, jobs (ORG and TITLE),
+// urls, images (PHOTO, the thumbnail url if any), the date of birth (BDAY) and the gender are encoded.
+// Lines end with CRLF and are folded at 75 octets.
+func (p *Person) VCard() string {
+	var buffer bytes.Buffer
+	writeVCardLine(&buffer, "BEGIN:VCARD")
+	writeVCardLine(&buffer, "VERSION:"+vCardVersion)
+
+	// Formatted name is required
+	formattedName := ""
+	if name := p.PrimaryName(); name != nil {
+		formattedName = nameDisplay(name)
+		if len(name.First) > 0 || len(name.Last) > 0 {
+			writeVCardLine(&buffer, "N:"+joinVCardValues(name.Last, name.First, name.Middle, name.Prefix, name.Suffix))
+		}
+	} else if len(p.Emails) > 0 {
+		formattedName = p.Emails[0].Address
+	}
+	writeVCardLine(&buffer, "FN:"+escapeVCardValue(formattedName))
+
+	for _, email := range p.Emails {
+		if len(email.Address) > 0 {
+			writeVCardLine(&buffer, "EMAIL"+vCardType(vCardEmailType(email.Type))+":"+escapeVCardValue(email.Address))
+		}
+	}
+
+	for _, phone := range p.Phones {
+		params := vCardType(vCardPhoneTypes[phone.Type])
+		switch e164 := phone.E164(); {
+		case len(e164) > 0:
+			uri := "tel:" + e164
+			if _, _, extension := phone.parts(); extension > 0 {
+				uri += ";ext=" + strconv.Itoa(extension)
+			}
+			writeVCardLine(&buffer, "TEL;VALUE=uri"+params+":"+uri)
+		case len(cmp.Or(phone.DisplayInternational, phone.Display, phone.Raw)) > 0:
+			writeVCardLine(&buffer, "TEL"+params+":"+escapeVCardValue(cmp.Or(phone.DisplayInternational, phone.Display, phone.Raw)))
+		}
+	}
+
+	for _, address := range p.Addresses {
+		street := strings.Join(nonEmpty(address.House, address.Street), " ")
+		if len(street) == 0 && len(address.City) == 0 && len(address.State) == 0 && len(address.ZipCode) == 0 {
+			street = cmp.Or(address.Raw, address.Display) // Not parsed
+		}
+		if len(street) > 0 || len(address.City) > 0 || len(address.POBox) > 0 {
+			writeVCardLine(&buffer, "ADR"+vCardType(vCardAddressType(address.Type))+":"+joinVCardValues(
+				address.POBox, address.Apartment, street, address.City, address.State, address.ZipCode, address.Country,
+			))
+		}
+	}
+
+	// Jobs are grouped, to keep the organization with the title
+	for index, job := range p.Jobs {
+		group := "job" + strconv.Itoa(index+1) + "."
+		if len(job.Organization) > 0 {
+			writeVCardLine(&buffer, group+"ORG:"+escapeVCardValue(job.Organization))
+		}
+		if title := cmp.Or(job.Title, job.Display); len(title) > 0 {
+			writeVCardLine(&buffer, group+"TITLE:"+escapeVCardValue(title))
+		}
+	}
+
+	for _, url := range p.URLs {
+		if len(url.URL) > 0 {
+			writeVCardLine(&buffer, "URL:"+url.URL)
+		}
+	}
+
+	for _, image := range p.Images {
+		if photo := cmp.Or(image.ThumbnailURL, image.URL); len(photo) > 0 {
+			writeVCardLine(&buffer, "PHOTO:"+photo)
+		}
+	}
+
+	if p.DateOfBirth != nil {
+		if birthday := vCardBirthday(p.DateOfBirth); len(birthday) > 0 {
+			writeVCardLine(&buffer, "BDAY:"+birthday)
+		} else if len(p.DateOfBirth.Display) > 0 {
+			writeVCardLine(&buffer, "BDAY;VALUE=text:"+escapeVCardValue(p.DateOfBirth.Display))
+		}
+	}
+
+	if p.Gender != nil {
+		switch p.Gender.Content {
+		case genderMale:
+			writeVCardLine(&buffer, "GENDER:M")
+		case genderFemale:
+			writeVCardLine(&buffer, "GENDER:F")
+		}
+	}
+
+	writeVCardLine(&buffer, "END:VCARD")
+	return buffer.String()
+}
+
+// ParseVCard will decode the first vCard (3.0 or 4.0) into a search person (see ParseVCards)
+func ParseVCard(data []byte) (*Person, []ParseNote, error) {
+	persons, notes, err := ParseVCards(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	return persons[0], notes[0], nil
+}
+
+// ParseVCards will decode all the vCards (for example, an exported .vcf file) into search persons.
+//
+// Names, emails, phones, addresses, jobs, urls, photos (urls only), the birthday and the gender are
+// decoded. Values go through the Add methods (AddEmail(), AddPhoneRaw(), AddURL(), SetDateOfBirth(),
+// etc.), and the notes of each person (by index) explain the values that were dropped.
+func ParseVCards(data []byte) ([]*Person, [][]ParseNote, error) {
+	var (
+		decoder *vCardDecoder
+		notes   [][]ParseNote
+		persons []*Person
+	)
+
+	for _, line := range unfoldVCard(data) {
+		property, ok := parseVCardLine(line)
+		if !ok {
+			continue
+		}
+
+		switch {
+		case property.name == "BEGIN" && strings.EqualFold(property.value, "VCARD"):
+			decoder = &vCardDecoder{groups: make(map[string]int), person: NewPerson()}
+		case decoder == nil:
+			continue
+		case property.name == "END" && strings.EqualFold(property.value, "VCARD"):
+			decoder.addJobs()
+			persons = append(persons, decoder.person)
+			notes = append(notes, decoder.notes)
+			decoder = nil
+		default:
+			decoder.add(property)
+		}
+	}
+
+	if len(persons) == 0 {
+		return nil, nil, ErrInvalidVCard
+	}
+	return persons, notes, nil
+}
+
+// vCardDecoder holds the person (and the notes) of the vCard being decoded
+type vCardDecoder struct {
+	groups map[string]int // Job index, by group
+	jobs   []Job          // Jobs (ORG and TITLE), added when the vCard ends
+	notes  []ParseNote
+	person *Person
+}
+
+// note records the error of an Add method (if any), returns true if the value was added
+func (d *vCardDecoder) note(field, text string, err error) bool {
+	if err != nil {
+		d.notes = append(d.notes, ParseNote{Err: err, Field: field, Message: "dropped: " + err.Error(), Text: text})
+		return false
+	}
+	return true
+}
+
+// add adds the value of the vCard property to the person
+func (d *vCardDecoder) add(property vCardProperty) {
+	p := d.person
+	types := property.types()
+
+	switch property.name {
+	case "FN":
+		// Only if there is no structured name
+		if value := unescapeVCardValue(property.value); len(value) > 0 && len(p.Names) == 0 {
+			d.note("names", value, p.AddNameRaw(value))
+		}
+	case "N":
+		parts := splitVCardValues(property.value, 5)
+		if len(strings.Join(parts, "")) > 0 &&
+			d.note("names", property.value, p.AddName(parts[1], parts[2], parts[0], parts[3], parts[4])) {
+			p.Names = p.Names[len(p.Names)-1:] // Replaces the formatted name
+		}
+	case "EMAIL":
+		value := strings.TrimPrefix(unescapeVCardValue(property.value), "mailto:")
+		if len(value) == 0 || !d.note("emails", value, p.AddEmail(value)) {
+			return
+		}
+		if types["work"] {
+			p.Emails[len(p.Emails)-1].Type = EmailTypeWork
+		} else if types["home"] {
+			p.Emails[len(p.Emails)-1].Type = EmailTypePersonal
+		}
+	case "TEL":
+		d.addPhone(property.value, types)
+	case "ADR":
+		d.addAddress(splitVCardValues(property.value, 7), types)
+	case "ORG", "TITLE":
+		d.addJob(property)
+	case "URL":
+		if value := unescapeVCardValue(property.value); len(value) > 0 {
+			d.note("urls", value, p.AddURL(value))
+		}
+	case "PHOTO":
+		// Only photo urls (inline data is dropped)
+		if value := unescapeVCardValue(property.value); len(value) > 0 {
+			d.note("images", value, p.AddImage(value))
+		}
+	case "BDAY":
+		start, end := parseVCardBirthday(property.value)
+		if len(start) == 0 {
+			d.notes = append(d.notes, ParseNote{Field: "dob", Message: "not a date, dropped", Text: property.value})
+			return
+		}
+		d.note("dob", property.value, p.SetDateOfBirth(start, end))
+	case "GENDER":
+		switch strings.ToUpper(splitVCardValues(property.value, 1)[0]) {
+		case "M":
+			d.note("gender", property.value, p.SetGender(genderMale))
+		case "F":
+			d.note("gender", property.value, p.SetGender(genderFemale))
+		}
+	}
+}
+
+// addAddress adds an ADR value, with AddAddress() if it has a house number, street and city or state
+// in the DefaultCountry, otherwise with AddAddressRaw()
+func (d *vCardDecoder) addAddress(parts []string, types map[string]bool) {
+	poBox, apartment, street, city, state, zipCode, country := parts[0], parts[1], parts[2], parts[3], parts[4], parts[5], parts[6]
+	text := strings.Join(nonEmpty(poBox, street, apartment, city, strings.Join(nonEmpty(state, zipCode), " "), country), ", ")
+	if len(street) == 0 && len(poBox) == 0 {
+		if len(text) > 0 {
+			d.notes = append(d.notes, ParseNote{Field: "addresses", Message: "no street or P.O. box, dropped", Text: text})
+		}
+		return
+	}
+
+	var err error
+	house, streetName, _ := strings.Cut(street, " ")
+	if len(house) > 0 && unicode.IsDigit(rune(house[0])) && len(streetName) > 0 && len(city)+len(state) > 0 &&
+		(len(country) == 0 || strings.EqualFold(country, DefaultCountry)) {
+		if err = d.person.AddAddress(house, streetName, apartment, city, state, country, poBox); err == nil {
+			d.person.Addresses[len(d.person.Addresses)-1].ZipCode = zipCode
+		}
+	} else {
+		err = d.person.AddAddressRaw(text)
+	}
+	if !d.note("addresses", text, err) {
+		return
+	}
+
+	if types["work"] {
+		d.person.Addresses[len(d.person.Addresses)-1].Type = AddressTypeWork
+	} else if types["home"] {
+		d.person.Addresses[len(d.person.Addresses)-1].Type = AddressTypeHome
+	}
+}
+
+// addJob records an ORG or TITLE value, properties in the same group (or ungrouped) are the same job
+func (d *vCardDecoder) addJob(property vCardProperty) {
+	index, ok := d.groups[property.group]
+	if ok && (property.name == "ORG" && len(d.jobs[index].Organization) > 0 ||
+		property.name == "TITLE" && len(d.jobs[index].Title) > 0) {
+		ok = false // Already set, a new job
+	}
+	if !ok {
+		d.jobs = append(d.jobs, Job{})
+		index = len(d.jobs) - 1
+		d.groups[property.group] = index
+	}
+	if property.name == "ORG" {
+		d.jobs[index].Organization = strings.Join(nonEmpty(splitVCardValues(property.value, 0)...), ", ")
+	} else {
+		d.jobs[index].Title = unescapeVCardValue(property.value)
+	}
+}
+
+// addJobs adds the jobs with AddJob() (a job needs a title and an organization)
+func (d *vCardDecoder) addJobs() {
+	for _, job := range d.jobs {
+		d.note("jobs", strings.Join(nonEmpty(job.Title, job.Organization), ", "),
+			d.person.AddJob(job.Title, job.Organization, "", "", ""))
+	}
+}
+
+// addPhone adds a TEL value (a tel: uri or text) to the person
+func (d *vCardDecoder) addPhone(value string, types map[string]bool) {
+	var extension int
+	number, ok := strings.CutPrefix(value, "tel:")
+	if ok {
+		var parameters string
+		number, parameters, _ = strings.Cut(number, ";")
+		for _, parameter := range strings.Split(parameters, ";") {
+			if ext, found := strings.CutPrefix(parameter, "ext="); found {
+				extension, _ = strconv.Atoi(ext)
+			}
+		}
+	} else {
+		number = unescapeVCardValue(value)
+	}
+	if len(number) == 0 || !d.note("phones", value, d.person.AddPhoneRaw(number)) {
+		return
+	}
+
+	phone := &d.person.Phones[len(d.person.Phones)-1]
+	if extension > 0 {
+		phone.Extension = extension
+	}
+	switch {
+	case types["cell"]:
+		phone.Type = PhoneTypeMobile
+	case types["pager"]:
+		phone.Type = PhoneTypePager
+	case types["fax"] && types["work"]:
+		phone.Type = PhoneTypeWorkFax
+	case types["fax"]:
+		phone.Type = PhoneTypeHomeFax
+	case types["work"]:
+		phone.Type = PhoneTypeWorkPhone
+	case types["home"]:
+		phone.Type = PhoneTypeHomePhone
+	}
+}
+
+// types returns the (lower case) TYPE parameter values of the property
+func (v vCardProperty) types() map[string]bool {
+	types := make(map[string]bool)
+	for _, value := range v.params["TYPE"] {
+		for _, t := range strings.Split(value, ",") {
+			types[strings.ToLower(strings.TrimSpace(t))] = true
+		}
+	}
+	return types
+}
+
+// writeVCardLine writes the content line, folded at 75 octets (without splitting characters)
+func writeVCardLine(buffer *bytes.Buffer, line string) {
+	length := 0
+	for _, r := range line {
+		size := utf8.RuneLen(r)
+		if length+size > vCardLineLength {
+			buffer.WriteString(vCardNewLine + " ")
+			length = 1
+		}
+		buffer.WriteRune(r)
+		length += size
+	}
+	buffer.WriteString(vCardNewLine)
+}
+
+// unfoldVCard returns the unfolded content lines
+func unfoldVCard(data []byte) []string {
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	text = strings.NewReplacer("\n ", "", "\n\t", "").Replace(text)
+	return strings.Split(text, "\n")
+}
+
+// parseVCardLine parses a content line: [group.]name[;param=value...]:value
+func parseVCardLine(line string) (vCardProperty, bool) {
+	// The value starts at the first colon that is not quoted (in a parameter)
+	quoted, separator := false, -1
+	for index, r := range line {
+		if r == '"' {
+			quoted = !quoted
+		} else if r == ':' && !quoted {
+			separator = index
+			break
+		}
+	}
+	if separator <= 0 {
+		return vCardProperty{}, false
+	}
+
+	property := vCardProperty{params: make(map[string][]string), value: line[separator+1:]}
+	parts := splitQuoted(line[:separator], ';')
+	property.name = strings.ToUpper(strings.TrimSpace(parts[0]))
+	if group, name, found := strings.Cut(property.name, "."); found {
+		property.group, property.name = group, name
+	}
+	for _, parameter := range parts[1:] {
+		key, value, found := strings.Cut(parameter, "=")
+		key = strings.ToUpper(strings.TrimSpace(key))
+		if !found {
+			// vCard 3.0 allows types without TYPE=, for example "TEL;CELL:..."
+			key, value = "TYPE", key
+		}
+		property.params[key] = append(property.params[key], strings.Trim(value, `"`))
+	}
+	return property, true
+}
+
+// splitQuoted splits the value on the separator, except in double quotes
+func splitQuoted(value string, separator rune) []string {
+	var parts []string
+	quoted, start := false, 0
+	for index, r := range value {
+		if r == '"' {
+			quoted = !quoted
+		} else if r == separator && !quoted {
+			parts = append(parts, value[start:index])
+			start = index + 1
+		}
+	}
+	return append(parts, value[start:])
+}
+
+// escapeVCardValue escapes a text value (backslash, comma, semicolon and new lines)
+func escapeVCardValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, ",", `\,`, ";", `\;`, "\r\n", `\n`, "\n", `\n`).Replace(value)
+}
+
+// unescapeVCardValue unescapes a text value
+func unescapeVCardValue(value string) string {
+	var builder strings.Builder
+	escaped := false
+	for _, r := range value {
+		switch {
+		case escaped && (r == 'n' || r == 'N'):
+			builder.WriteRune('\n')
+		case escaped:
+			builder.WriteRune(r)
+		case r == '\\':
+			escaped = true
+			continue
+		default:
+			builder.WriteRune(r)
+		}
+		escaped = false
+	}
+	return strings.TrimSpace(builder.String())
+}
+
+// joinVCardValues escapes and joins the components of a structured value (N, ADR)
+func joinVCardValues(values ...string) string {
+	for index := range values {
+		values[index] = escapeVCardValue(values[index])
+	}
+	return strings.Join(values, ";")
+}
+
+// splitVCardValues splits a structured value on the (unescaped) semicolons, and unescapes the
+// components. The result has at least count components.
+func splitVCardValues(value string, count int) []string {
+	var parts []string
+	escaped, start := false, 0
+	for index, r := range value {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == ';':
+			parts = append(parts, unescapeVCardValue(value[start:index]))
+			start = index + 1
+		}
+	}
+	parts = append(parts, unescapeVCardValue(value[start:]))
+	for len(parts) < count {
+		parts = append(parts, "")
+	}
+	return parts
+}
+
+// vCardType returns the TYPE parameter (quoted if it is a list), or empty
+func vCardType(value string) string {
+	switch {
+	case len(value) == 0:
+		return ""
+	case strings.Contains(value, ","):
+		return `;TYPE="` + value + `"`
+	}
+	return ";TYPE=" + value
+}
+
+// vCardEmailType returns the EMAIL type of the email @type
+func vCardEmailType(emailType EmailType) string {
+	switch emailType {
+	case EmailTypePersonal:
+		return "home"
+	case EmailTypeWork:
+		return "work"
+	}
+	return ""
+}
+
+// vCardAddressType returns the ADR type of the address @type
+func vCardAddressType(addressType AddressType) string {
+	switch addressType {
+	case AddressTypeHome:
+		return "home"
+	case AddressTypeWork:
+		return "work"
+	case AddressTypeOld:
+	}
+	return ""
+}
+
+// vCardBirthday returns the BDAY of the date of birth (a full date, or only the year or month), or empty
+func vCardBirthday(dob *DateOfBirth) string {
+	date := dob.BirthDate()
+	if date.Precision == DatePrecisionDay {
+		return date.Time.Format(vCardBirthdayLayout)
+	}
+	return date.String()
+}
+
+// parseVCardBirthday returns the date of birth range of the BDAY (YYYYMMDD, YYYY-MM-DD, YYYY-MM or YYYY), or empty
+func parseVCardBirthday(value string) (string, string) {
+	value = strings.TrimSpace(value)
+	if t, err := time.Parse(vCardBirthdayLayout, value); err == nil {
+		return t.Format("2006-01-02"), t.Format("2006-01-02")
+	}
+	date, err := ParseDate(value)
+	if err != nil || date.IsZero() || date.Precision == DatePrecisionSecond {
+		return "", ""
+	}
+	return date.Time.Format("2006-01-02"), date.End().Format("2006-01-02")
+}
+
+// nonEmpty returns the values that are not empty
+func nonEmpty(values ...string) []string {
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if len(value) > 0 {
+			result = append(result, value)
+		}
+	}
+	return result
+}
@@ -0,0 +1,256 @@
+package pipl
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestVCardPerson returns a person with all the vCard fields
+func newTestVCardPerson() *Person {
+	return &Person{
+		Names:  []Name{{First: "Clark", Middle: "Joseph", Last: "Kent", Display: "Clark Joseph Kent"}},
+		Emails: []Email{{Address: "clark@example.com", Type: EmailTypePersonal}, {Address: "ckent@dailyplanet.com", Type: EmailTypeWork}},
+		Phones: []Phone{
+			{CountryCode: 1, Number: 9785550145, Type: PhoneTypeMobile},
+			{CountryCode: 1, Number: 9785550146, Extension: 12, Type: PhoneTypeWorkPhone},
+			{Raw: "(978) 555-0147"},
+		},
+		Addresses: []Address{
+			{House: "344", Street: "Clinton St", Apartment: "3D", City: "Metropolis", State: "NY", ZipCode: "10001", Country: "US", Type: AddressTypeHome},
+			{Raw: "1 Kent Farm; Smallville, Kansas"},
+		},
+		Jobs: []Job{
+			{Title: "Reporter", Organization: "Daily Planet, Inc."},
+			{Title: "Farmer", Organization: "Kent Farm"},
+		},
+		URLs:        []URL{{URL: "https://example.com/clark"}},
+		Images:      []Image{{URL: "https://example.com/clark.jpg", ThumbnailURL: "https://thumb.example.com/clark.jpg"}},
+		DateOfBirth: &DateOfBirth{DateRange: DateRange{Start: "1986-06-18", End: "1986-06-18"}},
+		Gender:      &Gender{Content: genderMale},
+	}
+}
+
+// TestPerson_VCard will test encoding a person as a vCard
+func TestPerson_VCard(t *testing.T) {
+	t.Parallel()
+
+	t.Run("all fields", func(t *testing.T) {
+		card := newTestVCardPerson().VCard()
+		assert.Equal(t, strings.Join([]string{
+			"BEGIN:VCARD",
+			"VERSION:4.0",
+			"N:Kent;Clark;Joseph;;",
+			"FN:Clark Joseph Kent",
+			"EMAIL;TYPE=home:clark@example.com",
+			"EMAIL;TYPE=work:ckent@dailyplanet.com",
+			"TEL;VALUE=uri;TYPE=cell:tel:+19785550145",
+			`TEL;VALUE=uri;TYPE="voice,work":tel:+19785550146;ext=12`,
+			"TEL:(978) 555-0147",
+			"ADR;TYPE=home:;3D;344 Clinton St;Metropolis;NY;10001;US",
+			`ADR:;;1 Kent Farm\; Smallville\, Kansas;;;;`,
+			`job1.ORG:Daily Planet\, Inc.`,
+			"job1.TITLE:Reporter",
+			"job2.ORG:Kent Farm",
+			"job2.TITLE:Farmer",
+			"URL:https://example.com/clark",
+			"PHOTO:https://thumb.example.com/clark.jpg",
+			"BDAY:19860618",
+			"GENDER:M",
+			"END:VCARD",
+		}, "\r\n")+"\r\n", card)
+	})
+
+	t.Run("empty person", func(t *testing.T) {
+		assert.Equal(t, "BEGIN:VCARD\r\nVERSION:4.0\r\nFN:\r\nEND:VCARD\r\n", NewPerson().VCard())
+	})
+
+	t.Run("birthday precision", func(t *testing.T) {
+		tests := []struct {
+			dob      DateOfBirth
+			expected string
+		}{
+			{DateOfBirth{DateRange: DateRange{Start: "1986-01-01", End: "1986-12-31"}}, "BDAY:1986"},
+			{DateOfBirth{DateRange: DateRange{Start: "1986-02-01", End: "1986-02-28"}}, "BDAY:1986-02"},
+			{DateOfBirth{DateRange: DateRange{Start: "1986-06-18"}}, "BDAY:19860618"},
+			{DateOfBirth{DateRange: DateRange{Start: "1985-01-01", End: "1986-12-31"}, Display: "38 years old"}, "BDAY;VALUE=text:38 years old"},
+		}
+		for _, test := range tests {
+			person := &Person{DateOfBirth: &test.dob}
+			assert.Contains(t, person.VCard(), test.expected+"\r\n")
+		}
+	})
+
+	t.Run("line folding", func(t *testing.T) {
+		person := &Person{Names: []Name{{Display: strings.Repeat("Kal-El é ", 20)}}}
+		card := person.VCard()
+		for _, line := range strings.Split(strings.TrimSuffix(card, "\r\n"), "\r\n") {
+			assert.LessOrEqual(t, len(line), 75)
+			assert.True(t, utf8.ValidString(line))
+		}
+
+		// Unfolds to the same name
+		parsed, notes, err := ParseVCard([]byte(card))
+		require.NoError(t, err)
+		assert.Empty(t, notes)
+		require.Len(t, parsed.Names, 1)
+		assert.Equal(t, strings.TrimSpace(person.Names[0].Display), parsed.Names[0].Raw)
+	})
+}
+
+// TestParseVCard will test decoding vCards into search persons
+func TestParseVCard(t *testing.T) {
+	t.Parallel()
+
+	t.Run("round trip", func(t *testing.T) {
+		person, notes, err := ParseVCard([]byte(newTestVCardPerson().VCard()))
+		require.NoError(t, err)
+		assert.Empty(t, notes)
+
+		assert.Equal(t, []Name{{First: "Clark", Middle: "Joseph", Last: "Kent"}}, person.Names)
+		assert.Equal(t, []Email{
+			{Address: "clark@example.com", Type: EmailTypePersonal},
+			{Address: "ckent@dailyplanet.com", Type: EmailTypeWork},
+		}, person.Emails)
+		require.Len(t, person.Phones, 3)
+		assert.Equal(t, "+19785550145", person.Phones[0].Raw)
+		assert.Equal(t, PhoneTypeMobile, person.Phones[0].Type)
+		assert.Equal(t, "+19785550146", person.Phones[1].Raw)
+		assert.Equal(t, 12, person.Phones[1].Extension)
+		assert.Equal(t, PhoneTypeWorkPhone, person.Phones[1].Type)
+		assert.Equal(t, "+19785550147", person.Phones[2].Raw)
+		assert.Equal(t, []Address{
+			{Apartment: "3D", House: "344", Street: "Clinton St", City: "Metropolis", State: "NY", ZipCode: "10001", Country: "US", Type: AddressTypeHome},
+			{Raw: "1 Kent Farm; Smallville, Kansas"},
+		}, person.Addresses)
+		assert.Equal(t, []Job{{Title: "Reporter", Organization: "Daily Planet, Inc."}, {Title: "Farmer", Organization: "Kent Farm"}}, person.Jobs)
+		assert.Equal(t, []URL{{URL: "https://example.com/clark"}}, person.URLs)
+		assert.Equal(t, []Image{{URL: "https://thumb.example.com/clark.jpg"}}, person.Images)
+		assert.Equal(t, &DateOfBirth{DateRange: DateRange{Start: "1986-06-18", End: "1986-06-18"}}, person.DateOfBirth)
+		assert.Equal(t, &Gender{Content: genderMale}, person.Gender)
+	})
+
+	t.Run("vCard 3.0", func(t *testing.T) {
+		data := "BEGIN:VCARD\nVERSION:3.0\nFN:Lois Lane\nTEL;TYPE=WORK,FAX:+1 212 555 0100\nTEL;CELL:+1 212 555 0101\n" +
+			"EMAIL;TYPE=INTERNET:lois@example.com\nitem1.ORG:Daily Planet;Newsroom\nitem1.TITLE:Reporter\n" +
+			"BDAY:1987-02\nPHOTO;ENCODING=b;TYPE=JPEG:MIIE\nNOTE:Line one\\nLine\n  two\nEND:VCARD\n"
+		person, notes, err := ParseVCard([]byte(data))
+		require.NoError(t, err)
+		require.Len(t, notes, 1)
+		assert.Equal(t, "MIIE", notes[0].Text)
+		require.ErrorIs(t, notes[0].Err, ErrInvalidImageURL)
+
+		assert.Equal(t, []Name{{Raw: "Lois Lane"}}, person.Names)
+		require.Len(t, person.Phones, 2)
+		assert.Equal(t, "+12125550100", person.Phones[0].Raw)
+		assert.Equal(t, PhoneTypeWorkFax, person.Phones[0].Type)
+		assert.Equal(t, "+12125550101", person.Phones[1].Raw)
+		assert.Equal(t, PhoneTypeMobile, person.Phones[1].Type)
+		assert.Equal(t, []Email{{Address: "lois@example.com"}}, person.Emails)
+		assert.Equal(t, []Job{{Title: "Reporter", Organization: "Daily Planet, Newsroom"}}, person.Jobs)
+		assert.Equal(t, DateRange{Start: "1987-02-01", End: "1987-02-28"}, person.DateOfBirth.DateRange)
+		assert.Empty(t, person.Images)
+	})
+
+	t.Run("multiple vCards", func(t *testing.T) {
+		data := "BEGIN:VCARD\r\nFN:Clark Kent\r\nEND:VCARD\r\nBEGIN:VCARD\r\nFN:Lois Lane\r\nEND:VCARD\r\n"
+		persons, notes, err := ParseVCards([]byte(data))
+		require.NoError(t, err)
+		require.Len(t, persons, 2)
+		require.Len(t, notes, 2)
+		assert.Equal(t, "Lois Lane", persons[1].Names[0].Raw)
+	})
+
+	t.Run("quoted parameters and escaping", func(t *testing.T) {
+		data := "BEGIN:VCARD\r\nADR;LABEL=\"1 Main St: Apt 2\";TYPE=work:;;1 Main St;Smallville;KS;66002;US\r\n" +
+			"FN:Kent\\, Clark\\\\Jr\r\nEND:VCARD\r\n"
+		person, _, err := ParseVCard([]byte(data))
+		require.NoError(t, err)
+		assert.Equal(t, `Kent, Clark\Jr`, person.Names[0].Raw)
+		require.Len(t, person.Addresses, 1)
+		assert.Equal(t, AddressTypeWork, person.Addresses[0].Type)
+		assert.Equal(t, "Smallville", person.Addresses[0].City)
+	})
+
+	t.Run("invalid values are dropped with a note", func(t *testing.T) {
+		data := "BEGIN:VCARD\r\nFN:Kal\r\nEMAIL:clark.example.com\r\nEMAIL:clark@example.com\r\nTEL:12\r\n" +
+			"URL:a.io\r\nBDAY:someday\r\nEND:VCARD\r\n"
+		person, notes, err := ParseVCard([]byte(data))
+		require.NoError(t, err)
+
+		assert.Empty(t, person.Names)
+		assert.Equal(t, []Email{{Address: "clark@example.com"}}, person.Emails)
+		assert.Empty(t, person.Phones)
+		assert.Empty(t, person.URLs)
+		assert.Nil(t, person.DateOfBirth)
+
+		require.Len(t, notes, 5)
+		assert.Equal(t, "names", notes[0].Field)
+		require.ErrorIs(t, notes[0].Err, ErrNameTooShort)
+		assert.Equal(t, "clark.example.com", notes[1].Text)
+		require.ErrorIs(t, notes[1].Err, ErrInvalidEmailAddress)
+		assert.Equal(t, "phones", notes[2].Field)
+		require.Error(t, notes[2].Err)
+		require.ErrorIs(t, notes[3].Err, ErrURLTooShort)
+		assert.Equal(t, ParseNote{Field: "dob", Message: "not a date, dropped", Text: "someday"}, notes[4])
+	})
+
+	t.Run("addresses, jobs, photos and names go through the add methods", func(t *testing.T) {
+		data := "BEGIN:VCARD\r\nFN:Clark Kent\r\nN:;;Joseph;Mr.;\r\nADR:;;;;;;US\r\nADR:;;;;;;\r\n" +
+			"ADR;TYPE=work:;;Daily Planet Building;Metropolis;NY;10001;US\r\nADR:;;1 Chome-1;Tokyo;;100-0001;JP\r\n" +
+			"TITLE:Farmer\r\nitem1.ORG:Daily Planet\r\nitem1.TITLE:Reporter\r\nPHOTO:https://\r\nEND:VCARD\r\n"
+		person, notes, err := ParseVCard([]byte(data))
+		require.NoError(t, err)
+
+		// The formatted name is kept, the structured name was dropped
+		assert.Equal(t, []Name{{Raw: "Clark Kent"}}, person.Names)
+		assert.Equal(t, []Address{
+			{Raw: "Daily Planet Building, Metropolis, NY 10001, US", Type: AddressTypeWork},
+			{Raw: "1 Chome-1, Tokyo, 100-0001, JP"},
+		}, person.Addresses)
+		assert.Equal(t, []Job{{Title: "Reporter", Organization: "Daily Planet"}}, person.Jobs)
+		assert.Empty(t, person.Images)
+
+		require.Len(t, notes, 4)
+		require.ErrorIs(t, notes[0].Err, ErrMissingFirstLastName)
+		assert.Equal(t, ParseNote{Field: "addresses", Message: "no street or P.O. box, dropped", Text: "US"}, notes[1])
+		require.ErrorIs(t, notes[2].Err, ErrInvalidImageURL)
+		assert.Equal(t, "jobs", notes[3].Field)
+		assert.Equal(t, "Farmer", notes[3].Text)
+		require.ErrorIs(t, notes[3].Err, ErrMissingTitleOrOrganization)
+	})
+
+	t.Run("invalid vCard", func(t *testing.T) {
+		_, _, err := ParseVCard([]byte("FN:Clark Kent\r\n"))
+		require.ErrorIs(t, err, ErrInvalidVCard)
+
+		_, _, err = ParseVCard(nil)
+		require.ErrorIs(t, err, ErrInvalidVCard)
+	})
+}
+
+// ExamplePerson_VCard example using VCard()
+func ExamplePerson_VCard() {
+	person := NewPerson()
+	_ = person.AddName("Clark", "", "Kent", "", "")
+	_ = person.AddEmail("clark@example.com")
+	fmt.Print(strings.ReplaceAll(person.VCard(), "\r\n", "\n"))
+	// Output:BEGIN:VCARD
+	// VERSION:4.0
+	// N:Kent;Clark;;;
+	// FN:Clark Kent
+	// EMAIL:clark@example.com
+	// END:VCARD
+}
+
+// BenchmarkPerson_VCard benchmarks the method VCard()
+func BenchmarkPerson_VCard(b *testing.B) {
+	person := newTestVCardPerson()
+	for i := 0; i < b.N; i++ {
+		_ = person.VCard()
+	}
+}